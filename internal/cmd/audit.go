@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/osv"
+)
+
+// auditLock matches the advisories of a local OSV database against every
+// package in the lock file. An error is returned if any package is affected.
+func auditLock(p *download.Project, logger *log.Logger, out io.Writer, db string, asJSON bool) error {
+	if db == "" {
+		return fmt.Errorf("no advisory database provided, set --db")
+	}
+	advisories, err := osv.Load(db)
+	if err != nil {
+		return fmt.Errorf("load advisories: %v", err)
+	}
+	logger.Printf("loaded %d advisories from %s", len(advisories), db)
+
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+	vulns := []download.Vulnerability{}
+	affected := 0
+	for _, pkg := range l.Import {
+		found, err := p.Audit(pkg, advisories)
+		if err != nil {
+			logger.Printf("warning: package %s: %v", pkg.Package, err)
+		}
+		if len(found) > 0 {
+			affected++
+		}
+		vulns = append(vulns, found...)
+	}
+
+	if asJSON {
+		e := json.NewEncoder(out)
+		e.SetIndent("", "  ")
+		if err := e.Encode(vulns); err != nil {
+			return err
+		}
+	} else if len(vulns) > 0 {
+		tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "PACKAGE\tVERSION\tADVISORY\tFIXED\tSUMMARY")
+		for _, v := range vulns {
+			id := v.ID
+			if len(v.Aliases) > 0 {
+				id += " (" + strings.Join(v.Aliases, ", ") + ")"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", v.Package, v.Version, id, displayList(v.Fixed), v.Summary)
+			for _, imp := range v.Imports {
+				symbols := "all symbols"
+				if len(imp.Symbols) > 0 {
+					symbols = strings.Join(imp.Symbols, ", ")
+				}
+				fmt.Fprintf(tw, "\t\t\t\t  %s: %s\n", imp.Path, symbols)
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if affected > 0 {
+		return fmt.Errorf("%d of %d packages affected by %d advisories", affected, len(l.Import), len(vulns))
+	}
+	logger.Printf("no advisories affect the %d locked packages", len(l.Import))
+	return nil
+}
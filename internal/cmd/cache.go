@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+func listCache(cache download.Cache, out io.Writer) error {
+	entries, err := cache.Entries()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "REMOTE\tSIZE\tLAST USED\tDIRECTORY")
+	for _, e := range entries {
+		remote := e.Remote
+		if remote == "" {
+			remote = "unknown"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			remote, formatSize(e.Size), e.LastUsed.Local().Format("2006-01-02 15:04"), e.Dir)
+	}
+	return tw.Flush()
+}
+
+func cacheUsage(cache download.Cache, out io.Writer) error {
+	entries, err := cache.Entries()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	_, err = fmt.Fprintf(out, "%s in %d repos\n", formatSize(total), len(entries))
+	return err
+}
+
+func pruneCache(cache download.Cache, logger *log.Logger, olderThan string) error {
+	d, err := parseAge(olderThan)
+	if err != nil {
+		return err
+	}
+	pruned, err := cache.Prune(time.Now().Add(-d))
+	for _, e := range pruned {
+		remote := e.Remote
+		if remote == "" {
+			remote = e.Dir
+		}
+		logger.Printf("removed %s (%s)", remote, formatSize(e.Size))
+	}
+	return err
+}
+
+// parseAge parses a Go duration, also accepting a whole number of days such as
+// "30d".
+func parseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// formatSize formats a number of bytes using binary units.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -39,19 +40,24 @@ func (o *options) project() (*download.Project, error) {
 		dir = cwd
 	}
 
-	var cache download.Cache
-	if o.disableCache {
-		cache = download.NoCache
-	} else {
-		home, err := homedir.Dir()
-		if err != nil {
-			return nil, fmt.Errorf("could not find home directory: %v", err)
-		}
-		cache = download.NewCache(filepath.Join(home, ".godl"))
+	cache, err := o.cache()
+	if err != nil {
+		return nil, err
 	}
 	return &download.Project{Dir: dir, Cache: cache}, nil
 }
 
+func (o *options) cache() (download.Cache, error) {
+	if o.disableCache {
+		return download.NoCache, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return nil, fmt.Errorf("could not find home directory: %v", err)
+	}
+	return download.NewCache(filepath.Join(home, ".godl")), nil
+}
+
 // New returns a new instance of the godl command.
 func New() *cobra.Command {
 	o := new(options)
@@ -67,6 +73,21 @@ func New() *cobra.Command {
 	}
 	c.AddCommand(cmdVendor(o, l))
 	c.AddCommand(cmdImport(o, l))
+	c.AddCommand(cmdGet(o, l))
+	c.AddCommand(cmdInit(o, l))
+	c.AddCommand(cmdRemove(o, l))
+	c.AddCommand(cmdStatus(o))
+	c.AddCommand(cmdUpdate(o, l))
+	c.AddCommand(cmdOutdated(o, l))
+	c.AddCommand(cmdCache(o, l))
+	c.AddCommand(cmdVerify(o, l))
+	c.AddCommand(cmdList(o))
+	c.AddCommand(cmdExport(o, l))
+	c.AddCommand(cmdSBOM(o, l))
+	c.AddCommand(cmdLicenses(o))
+	c.AddCommand(cmdAudit(o, l))
+	c.AddCommand(cmdGraph(o))
+	c.AddCommand(cmdWhy(o, l))
 
 	c.PersistentFlags().BoolVar(&o.disableCache, "disable-cache", false,
 		"Disable download cache.")
@@ -78,7 +99,40 @@ func New() *cobra.Command {
 	return c
 }
 
+// Execute runs the godl command with the program's arguments.
+func Execute() error {
+	c := New()
+	c.SetArgs(goFlagArgs(c, os.Args[1:]))
+	return c.Execute()
+}
+
+// goFlagArgs lets flags with long names be passed with a single dash, the way
+// the go tool accepts them, such as "godl list -json". Arguments naming a long
+// flag of the sub-command are rewritten to use two dashes.
+func goFlagArgs(c *cobra.Command, args []string) []string {
+	sub, _, err := c.Find(args)
+	if err != nil {
+		return args
+	}
+	rewritten := make([]string, len(args))
+	copy(rewritten, args)
+	for i, arg := range rewritten {
+		if arg == "--" {
+			break
+		}
+		if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+			continue
+		}
+		name := strings.SplitN(arg[1:], "=", 2)[0]
+		if len(name) > 1 && (sub.Flags().Lookup(name) != nil || sub.InheritedFlags().Lookup(name) != nil) {
+			rewritten[i] = "-" + arg
+		}
+	}
+	return rewritten
+}
+
 func cmdVendor(o *options, l *log.Logger) *cobra.Command {
+	var dryRun bool
 	c := &cobra.Command{
 		Use:   "vendor",
 		Short: "Download dependencies to the vendor directory",
@@ -86,7 +140,12 @@ func cmdVendor(o *options, l *log.Logger) *cobra.Command {
 			Load the manifest file and compare it against the lock file for any dependencies
 			that need downloading, removal, or updating. Dependencies are then modified one
 			at a time.
+
+			If the manifest has a license policy, the licenses of every vendored package
+			are checked afterwards and the command fails if any violate it. See
+			'godl licenses' for details.
 		`),
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 0 {
 				return fmt.Errorf("surplus arguments")
@@ -95,34 +154,685 @@ func cmdVendor(o *options, l *log.Logger) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return downloadAll(p, l)
+			return downloadAll(p, l, cmd.OutOrStdout(), dryRun)
 		},
 	}
+	c.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Print the changes that would be made without modifying anything.")
 	return c
 }
 
 func cmdImport(o *options, l *log.Logger) *cobra.Command {
+	var opts importOptions
 	c := &cobra.Command{
-		Use:   "import",
+		Use:   "import [file]",
 		Short: "Import dependencies from an existing package management file",
 		Example: indent("  ", `
+			godl import
 			godl import Godeps/Godeps.json
 			godl import glide.yaml
+			godl import vendor/manifest
+			godl import Gopkg.lock
+			godl import vendor/vendor.json
+			godl import go.mod
+			godl import vendor.conf
+			godl import --merge ../other-service/glide.lock --prefer=imported
 		`),
 		Long: indent("", `
 			Inspect an existing manifest file from another package manager. Supported
-			tools are godeps, glide, and gvt.
+			tools are godeps, glide, gvt, dep, govendor, vndr, trash, and Go modules.
+
+			If no file is provided, the project directory is searched for every
+			supported file and the most precise one is used. Files that pin exact
+			revisions, such as lock files, are preferred over files that allow version
+			ranges.
+
+			Files are recognized by name. A godeps, govendor or gvt file saved under
+			another name is recognized by its contents.
+
+			By default the manifest file must not exist. With --merge, imported packages
+			are added to the existing manifest instead. Packages in both manifests have
+			their subpackages combined, and conflicting versions are printed and resolved
+			using --prefer.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("surplus arguments")
+			}
+
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			var manifest string
+			if len(args) == 1 {
+				manifest = args[0]
+			}
+			return importManifest(p, l, cmd.OutOrStdout(), manifest, opts)
+		},
+	}
+	c.Flags().BoolVar(&opts.merge, "merge", false,
+		"Merge imported packages into the existing manifest.")
+	c.Flags().StringVar(&opts.prefer, "prefer", preferExisting,
+		"Which version wins conflicts when merging, 'existing' or 'imported'.")
+	return c
+}
+
+func cmdGet(o *options, l *log.Logger) *cobra.Command {
+	var remote string
+	c := &cobra.Command{
+		Use:   "get [package[@version]...]",
+		Short: "Add or update individual dependencies",
+		Example: indent("  ", `
+			godl get golang.org/x/net@feeb485667d1fdabe727840fe00adc22431bc86e
+			godl get gopkg.in/square/go-jose.v2@v2.1.0 --remote git@github.com:square/go-jose.git
+			godl get github.com/spf13/cobra github.com/spf13/pflag
+		`),
+		Long: indent("", `
+			Add or update entries in the manifest file, download only those packages to
+			the vendor directory, then update the lock file. Packages without a version
+			keep the version of their existing manifest entry, and new packages default
+			to the latest revision. Naming a package within a repo adds it as a
+			subpackage of the repo's root package.
+
+			Comments in the manifest file are kept.
+
+			If --remote isn't provided, the remote of an existing manifest entry is kept.
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
-				return fmt.Errorf("import command requires argument")
+			if len(args) == 0 {
+				return fmt.Errorf("get command requires at least one package")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
 			}
+			return getPackages(p, l, args, remote)
+		},
+	}
+	c.Flags().StringVar(&remote, "remote", "",
+		"Remote repo to download the package from. Only valid for a single package.")
+	return c
+}
 
+func cmdInit(o *options, l *log.Logger) *cobra.Command {
+	var fromVendor bool
+	c := &cobra.Command{
+		Use:   "init",
+		Short: "Create manifest and lock files for a project",
+		Example: indent("  ", `
+			godl init
+			godl init --from-vendor
+		`),
+		Long: indent("", `
+			Create empty manifest and lock files. With --from-vendor, the files instead
+			describe the packages already checked into the vendor directory, grouped by
+			the root of their repo. Versions of those packages aren't known and should
+			be pinned afterwards with 'godl get'.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
 			p, err := o.project()
 			if err != nil {
 				return err
 			}
-			return importManifest(p, l, args[0])
+			return initProject(p, l, fromVendor)
+		},
+	}
+	c.Flags().BoolVar(&fromVendor, "from-vendor", false,
+		"Populate the manifest and lock files from the existing vendor directory.")
+	return c
+}
+
+func cmdRemove(o *options, l *log.Logger) *cobra.Command {
+	var force bool
+	c := &cobra.Command{
+		Use:   "remove [package...]",
+		Short: "Remove dependencies from the project",
+		Example: indent("  ", `
+			godl remove github.com/spf13/cobra
+		`),
+		Long: indent("", `
+			Remove packages from the manifest and lock files, and delete them from the
+			vendor directory. Packages still imported by Go files in the project are
+			refused unless --force is provided.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("remove command requires at least one package")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return removePackages(p, l, args, force)
+		},
+	}
+	c.Flags().BoolVar(&force, "force", false,
+		"Remove packages even if the project still imports them.")
+	return c
+}
+
+func cmdStatus(o *options) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "status",
+		Short: "Show the changes 'godl vendor' would make",
+		Long: indent("", `
+			Compare the manifest, lock file, and vendor directory and print each package
+			that would be added, updated, removed, or restored by 'godl vendor'. For
+			updates, the fields that differ between the manifest and lock file are shown.
+			Packages in the vendor directory that aren't in the lock file are listed as
+			untracked.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			plan, err := p.Plan()
+			if err != nil {
+				return err
+			}
+			return printPlan(cmd.OutOrStdout(), plan)
+		},
+	}
+	return c
+}
+
+func cmdUpdate(o *options, l *log.Logger) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "update [package...]",
+		Short: "Move floating dependencies to their latest revision",
+		Example: indent("  ", `
+			godl update
+			godl update github.com/spf13/cobra
+		`),
+		Long: indent("", `
+			Fetch the remotes of dependencies that have no version or track a branch,
+			and re-vendor those whose latest revision differs from the lock file. The
+			old and new revisions of each updated package are printed. Dependencies
+			pinned to a tag or revision are left alone.
+
+			If no packages are provided, all packages in the manifest are checked.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return updatePackages(p, l, cmd.OutOrStdout(), args)
+		},
+	}
+	return c
+}
+
+func cmdOutdated(o *options, l *log.Logger) *cobra.Command {
+	var asJSON bool
+	c := &cobra.Command{
+		Use:   "outdated",
+		Short: "Report newer tags and commits for each dependency",
+		Example: indent("  ", `
+			godl outdated
+			godl outdated --json
+		`),
+		Long: indent("", `
+			Fetch the remote of every package in the lock file and report the newest
+			semantic version tag, how many commits the default branch is ahead of the
+			locked revision, and the age of the locked revision.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return reportOutdated(p, l, cmd.OutOrStdout(), asJSON)
+		},
+	}
+	c.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON.")
+	return c
+}
+
+func cmdCache(o *options, l *log.Logger) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cache [sub-command]",
+		Short: "Inspect and clean up the download cache",
+		Long: indent("", `
+			Manage the repos godl keeps in its download cache. Each cached repo is
+			stored under a hashed directory name, with metadata recording its remote
+			and when it was last used.
+		`),
+	}
+
+	withCache := func(f func(cache download.Cache, out io.Writer) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			cache, err := o.cache()
+			if err != nil {
+				return err
+			}
+			return f(cache, cmd.OutOrStdout())
+		}
+	}
+
+	c.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List cached repos with their remote, size and last use",
+		RunE:  withCache(listCache),
+	})
+	c.AddCommand(&cobra.Command{
+		Use:   "du",
+		Short: "Print the total size of the cache",
+		RunE:  withCache(cacheUsage),
+	})
+	c.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached repo",
+		RunE: withCache(func(cache download.Cache, out io.Writer) error {
+			return cache.Clear()
+		}),
+	})
+
+	var olderThan string
+	prune := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached repos that haven't been used recently",
+		Example: indent("  ", `
+			godl cache prune --older-than 30d
+			godl cache prune --older-than 72h
+		`),
+		RunE: withCache(func(cache download.Cache, out io.Writer) error {
+			return pruneCache(cache, l, olderThan)
+		}),
+	}
+	prune.Flags().StringVar(&olderThan, "older-than", "30d",
+		"Remove repos unused for this long. Accepts Go durations and a 'd' suffix for days.")
+	c.AddCommand(prune)
+	return c
+}
+
+func cmdVerify(o *options, l *log.Logger) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that vendored files match the lock file",
+		Long: indent("", `
+			Recompute the hash of each package in the vendor directory and compare it
+			against the hash recorded in the lock file. Packages that are missing, whose
+			files were modified, or that have no hash recorded are listed, and the
+			command exits with a non-zero status. Re-download packages locked before
+			hashes were recorded to add one.
+		`),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return verifyVendor(p, l, cmd.OutOrStdout())
+		},
+	}
+	return c
+}
+
+func cmdList(o *options) *cobra.Command {
+	var (
+		format     string
+		asJSON     bool
+		mismatched bool
+	)
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "List the project's dependencies",
+		Example: indent("  ", `
+			godl list
+			godl list -f '{{.Package}} {{.Version}}'
+			godl list -f '{{.Package}} {{join .Subpackages ","}}'
+			godl list -json
+			godl list --mismatched
+		`),
+		Long: indent("", `
+			Print the packages in the lock file, along with packages that are only in
+			the manifest. The -f flag takes a Go template which is executed for each
+			package with the following fields:
+
+			    Package      Import path of the repo's root package.
+			    Version      Locked version, or manifest version if not locked.
+			    Revision     Revision the version resolved to.
+			    Remote       Remote the package is downloaded from, if not the default.
+			    Subpackages  Subpackages to vendor.
+			    Hash         Hash of the vendored files.
+			    Dir          Directory the package is vendored to.
+			    InManifest   Whether the package is in the manifest.
+			    InLock       Whether the package is in the lock file.
+
+			The template function "join" is available to format subpackages.
+
+			As with go list, flags may be given with a single dash, such as -json.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			if format != "" && asJSON {
+				return fmt.Errorf("--format and --json are mutually exclusive")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return listPackages(p, cmd.OutOrStdout(), format, asJSON, mismatched)
+		},
+	}
+	c.Flags().StringVarP(&format, "format", "f", "", "Go template to format each package with.")
+	c.Flags().BoolVar(&asJSON, "json", false, "Print each package as JSON.")
+	c.Flags().BoolVar(&mismatched, "mismatched", false,
+		"Only list packages that are in just one of the manifest or lock file.")
+	return c
+}
+
+func cmdExport(o *options, l *log.Logger) *cobra.Command {
+	var (
+		format, output string
+		write          bool
+	)
+	c := &cobra.Command{
+		Use:   "export [sub-command]",
+		Short: "Describe the lock file in the formats of other tools",
+		Example: indent("  ", `
+			godl export --format=glide
+			godl export --format=godeps --write
+			godl export --format=dep -o Gopkg.lock
+			godl export --format=vendor.conf -o vendor.conf
+		`),
+		Long: indent("", `
+			Write the pinned revisions of the lock file in the format of another tool,
+			so projects using that tool can consume the exact same dependencies. This is
+			the reverse of 'godl import'. Supported formats are godeps, glide, dep, and
+			vendor.conf. The file is written to stdout unless --output is provided, or
+			--write is set to write it where the tool expects it, such as
+			Godeps/Godeps.json for godeps.
+
+			Subpackages and remotes are included when the format supports them. godeps
+			files also record the project's import path, read from go.mod or the
+			project's location in GOPATH, and the Go version of go.mod's go directive or
+			of the running Go release.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			if format == "" {
+				return fmt.Errorf("--format is required")
+			}
+			if output != "" && write {
+				return fmt.Errorf("--output and --write are mutually exclusive")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return exportLock(p, l, cmd.OutOrStdout(), format, output, write)
+		},
+	}
+	c.Flags().StringVar(&format, "format", "",
+		"Format to export, one of godeps, glide, dep or vendor.conf.")
+	c.Flags().StringVarP(&output, "output", "o", "",
+		"Path to write the file to. Defaults to stdout.")
+	c.Flags().BoolVarP(&write, "write", "w", false,
+		"Write the file to the path the tool expects within the project.")
+
+	var modulePath string
+	gomod := &cobra.Command{
+		Use:   "gomod",
+		Short: "Write go.mod and vendor/modules.txt files for module-aware builds",
+		Example: indent("  ", `
+			godl export gomod
+			godl export gomod --module github.com/example/service
+		`),
+		Long: indent("", `
+			Write a go.mod file requiring each package of the lock file, and a matching
+			vendor/modules.txt so the vendor directory can be used with -mod=vendor.
+			Semantic version tags are used as module versions. Other versions are
+			converted to pseudo-versions using the commit date of the locked revision
+			in the download cache.
+
+			The module path and go directive of an existing go.mod are kept, other
+			directives are replaced.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return exportGoMod(p, l, modulePath)
+		},
+	}
+	gomod.Flags().StringVar(&modulePath, "module", "",
+		"Module path of the project. Defaults to the path in the existing go.mod.")
+	c.AddCommand(gomod)
+	return c
+}
+
+func cmdSBOM(o *options, l *log.Logger) *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "sbom",
+		Short: "Generate a software bill of materials for vendored dependencies",
+		Example: indent("  ", `
+			godl sbom --format spdx-json > sbom.spdx.json
+			godl sbom --format cyclonedx-json > bom.json
+			godl sbom --format cyclonedx-xml > bom.xml
+		`),
+		Long: indent("", `
+			Write a software bill of materials describing every package of the lock file.
+			Each package records its remote, version, revision, the licenses detected in
+			its license files, and checksums of its vendored files. Supported formats are
+			SPDX JSON and CycloneDX JSON or XML.
+
+			CycloneDX documents also list each vendored subpackage as a component, and
+			record which vendored packages import which others.
+
+			Only local data is used: the lock file, the vendor directory, and the
+			download cache, which provides the date of each revision if available.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return writeSBOM(p, l, cmd.OutOrStdout(), format)
+		},
+	}
+	c.Flags().StringVar(&format, "format", "spdx-json",
+		"Format of the document, one of spdx-json, cyclonedx-json or cyclonedx-xml.")
+	return c
+}
+
+func cmdLicenses(o *options) *cobra.Command {
+	var asJSON bool
+	c := &cobra.Command{
+		Use:   "licenses",
+		Short: "List the licenses of vendored dependencies",
+		Example: indent("  ", `
+			godl licenses
+			godl licenses --json
+		`),
+		Long: indent("", `
+			Classify the license files of every package in the lock file by comparing
+			them against bundled license texts. Recognized licenses are MIT,
+			BSD-2-Clause, BSD-3-Clause, Apache-2.0, MPL-2.0, GPL-2.0-only, GPL-3.0-only,
+			LGPL-2.1-only and LGPL-3.0-only. LICENSE, LICENCE, COPYING, NOTICE and
+			PATENTS files are kept when vendoring, along with variants such as
+			LICENSE-MIT and COPYING.LESSER.
+
+			The manifest can restrict the licenses of dependencies using SPDX
+			identifiers:
+
+			    licenses:
+			      allow: [MIT, BSD-3-Clause, Apache-2.0]
+			      deny: [GPL-3.0-only]
+
+			License texts don't say whether later versions of a GNU license may be used,
+			so the -only and -or-later identifiers of a license match the same files.
+			Deprecated identifiers such as GPL-3.0 are also accepted.
+
+			Packages without license files always violate the policy. With an allow
+			list, every license found must be allowed and unrecognized licenses are
+			rejected. Violations are reported here and fail 'godl vendor'.
+		`),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return reportLicenses(p, cmd.OutOrStdout(), asJSON)
+		},
+	}
+	c.Flags().BoolVar(&asJSON, "json", false, "Print the licenses as JSON.")
+	return c
+}
+
+func cmdAudit(o *options, l *log.Logger) *cobra.Command {
+	var (
+		db     string
+		asJSON bool
+	)
+	c := &cobra.Command{
+		Use:   "audit",
+		Short: "Check dependencies against a local vulnerability database",
+		Example: indent("  ", `
+			godl audit --db ./vulndb
+			godl audit --db ./vulndb --json
+		`),
+		Long: indent("", `
+			Match every package in the lock file against advisories in the OSV format,
+			such as a copy of the Go vulnerability database. Every JSON file under the
+			--db directory is read and nothing is downloaded.
+
+			Packages locked to a tag are compared against each advisory's version
+			ranges. Git commit ranges, and the ranges of packages locked to a branch or
+			revision, are evaluated against the history of the package's remote in the
+			cache. Advisories that can't be evaluated are reported as warnings.
+
+			Advisories for modules nested within a package, such as major version
+			suffixes like "/v2", aren't versioned by the package's tags. They match
+			when the package's subpackages include the module, or one of its affected
+			imports.
+
+			Affected packages are listed along with the affected import paths and
+			symbols when the advisory provides them, and the command exits with a
+			non-zero status.
+		`),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return auditLock(p, l, cmd.OutOrStdout(), db, asJSON)
+		},
+	}
+	c.Flags().StringVar(&db, "db", "", "Directory of OSV advisories.")
+	c.Flags().BoolVar(&asJSON, "json", false, "Print the affected packages as JSON.")
+	return c
+}
+
+func cmdGraph(o *options) *cobra.Command {
+	var opts graphOptions
+	c := &cobra.Command{
+		Use:   "graph",
+		Short: "Print the import graph of the project and its dependencies",
+		Example: indent("  ", `
+			godl graph | dot -Tsvg > imports.svg
+			godl graph --level repo
+			godl graph --root github.com/example/app/cmd/server --format json
+		`),
+		Long: indent("", `
+			Parse the imports of the project's packages and the vendored packages, and
+			print the graph between them as Graphviz DOT or JSON. Imports of other
+			packages, such as the standard library, and test files are ignored. Project
+			packages are named using the module path of go.mod, or the project's path
+			in GOPATH, and are drawn as boxes. The command fails if neither is found.
+
+			With --level repo, packages are grouped by their lock file entry and the
+			project's packages are grouped into a single node. The --root flag limits
+			the graph to what a package, or any package within it, imports.
+		`),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			return printGraph(p, cmd.OutOrStdout(), opts)
+		},
+	}
+	c.Flags().StringVar(&opts.format, "format", graphDOT, "Output format, either 'dot' or 'json'.")
+	c.Flags().StringVar(&opts.level, "level", levelPackage, "Graph granularity, either 'package' or 'repo'.")
+	c.Flags().StringSliceVar(&opts.roots, "root", nil, "Only include what this package imports. Can be repeated.")
+	return c
+}
+
+func cmdWhy(o *options, l *log.Logger) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "why [package]",
+		Short: "Explain why a vendored package is needed",
+		Example: indent("  ", `
+			godl why github.com/golang/protobuf/proto
+			godl why
+		`),
+		Long: indent("", `
+			Print the shortest chain of imports from the project's code to a vendored
+			package, or any package within it. One chain is printed for each main
+			package, and each package not imported by other project packages, that
+			depends on it. Vendored packages within it that the project doesn't import
+			are listed after the chains. Test files are ignored. Like 'godl graph', the
+			project's import path is read from go.mod or its location in GOPATH.
+
+			Without arguments, list the lock entries and vendored subpackages that
+			aren't imported by any project package.
+		`),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("surplus arguments")
+			}
+			p, err := o.project()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				return reportUnreachable(p, l, cmd.OutOrStdout())
+			}
+			return explainImport(p, cmd.OutOrStdout(), args[0])
 		},
 	}
 	return c
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGoFlagArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"list", "-json"}, []string{"list", "--json"}},
+		{[]string{"list", "--json"}, []string{"list", "--json"}},
+		{[]string{"list", "-f", "{{.Package}}"}, []string{"list", "-f", "{{.Package}}"}},
+		{[]string{"list", "-mismatched", "-dir=../other"}, []string{"list", "--mismatched", "--dir=../other"}},
+		{[]string{"outdated", "-json", "-v"}, []string{"outdated", "--json", "-v"}},
+		{[]string{"list", "--", "-json"}, []string{"list", "--", "-json"}},
+		{[]string{"vendor", "-json"}, []string{"vendor", "-json"}},
+	}
+	for _, test := range tests {
+		got := goFlagArgs(New(), test.args)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("goFlagArgs(%q), want=%q, got=%q", test.args, test.want, got)
+		}
+	}
+}
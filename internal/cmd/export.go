@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/exporter"
+)
+
+// exportLock writes the lock file in the format of another tool to the output
+// path, or out if no path is provided. If write is set, the file is written to
+// the location the tool expects within the project.
+func exportLock(p *download.Project, logger *log.Logger, out io.Writer, format, output string, write bool) error {
+	e, err := exporter.ForFormat(format)
+	if err != nil {
+		return err
+	}
+	if write {
+		output = filepath.Join(p.Dir, filepath.FromSlash(e.File()))
+	}
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return e.Export(out, p, l, logger)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := e.Export(buf, p, l, logger); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	logger.Printf("exported %d packages to %s", len(l.Import), output)
+	return nil
+}
+
+// exportGoMod writes go.mod and vendor/modules.txt files describing the lock
+// file. The module path and go directive of an existing go.mod are kept,
+// unless modulePath is set.
+func exportGoMod(p *download.Project, logger *log.Logger, modulePath string) error {
+	goModPath := filepath.Join(p.Dir, "go.mod")
+	var goVersion string
+	existing, err := download.ReadGoMod(goModPath)
+	switch {
+	case err == nil:
+		goVersion = existing.Go
+		if modulePath == "" {
+			modulePath = existing.Module
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+	if modulePath == "" {
+		return fmt.Errorf("no module path found in go.mod, provide one with --module")
+	}
+
+	mods, err := p.Modules()
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	writeGoMod(buf, modulePath, goVersion, mods)
+	if err := ioutil.WriteFile(goModPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	writeModulesTxt(buf, mods)
+	vendorDir := filepath.Join(p.Dir, "vendor")
+	if err := os.MkdirAll(vendorDir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(vendorDir, "modules.txt"), buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	logger.Printf("exported %d modules to go.mod and vendor/modules.txt", len(mods))
+	return nil
+}
+
+func writeGoMod(w io.Writer, modulePath, goVersion string, mods []download.Module) {
+	fmt.Fprintf(w, "module %s\n", modulePath)
+	if goVersion != "" {
+		fmt.Fprintf(w, "\ngo %s\n", goVersion)
+	}
+	if len(mods) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nrequire (\n")
+	for _, mod := range mods {
+		fmt.Fprintf(w, "\t%s %s\n", mod.Path, mod.Version)
+	}
+	fmt.Fprintf(w, ")\n")
+}
+
+func writeModulesTxt(w io.Writer, mods []download.Module) {
+	for _, mod := range mods {
+		fmt.Fprintf(w, "# %s %s\n", mod.Path, mod.Version)
+		fmt.Fprintf(w, "## explicit\n")
+		for _, pkg := range mod.Packages {
+			fmt.Fprintf(w, "%s\n", pkg)
+		}
+	}
+}
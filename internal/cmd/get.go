@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/forked/glideutil"
+)
+
+// parsePackageArg splits a "pkg[@version]" argument into its root package, the
+// subpackage relative to that root, and the requested version.
+func parsePackageArg(arg string) (rootPkg, subPkg, version string, err error) {
+	pkg := arg
+	if i := strings.Index(arg, "@"); i >= 0 {
+		pkg, version = arg[:i], arg[i+1:]
+		if version == "" {
+			return "", "", "", fmt.Errorf("invalid argument %q: empty version", arg)
+		}
+	}
+	pkg = strings.TrimSuffix(pkg, "/")
+	if pkg == "" {
+		return "", "", "", fmt.Errorf("invalid argument %q: empty package", arg)
+	}
+
+	rootPkg, err = glideutil.GetRootFromPackage(pkg)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to determine root package of %s: %v", pkg, err)
+	}
+	subPkg = strings.TrimPrefix(strings.TrimPrefix(pkg, rootPkg), "/")
+	return rootPkg, subPkg, version, nil
+}
+
+// getPackages adds or updates the manifest entry for each argument, downloads
+// the package, and records the result in the lock file.
+func getPackages(p *download.Project, logger *log.Logger, args []string, remote string) error {
+	if remote != "" && len(args) != 1 {
+		return fmt.Errorf("--remote can only be used with a single package")
+	}
+
+	m, err := p.LoadManifest()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		m = new(download.Manifest)
+	}
+
+	for _, arg := range args {
+		rootPkg, subPkg, version, err := parsePackageArg(arg)
+		if err != nil {
+			return err
+		}
+
+		pkg := download.ManifestPackage{Package: rootPkg}
+		for _, existing := range m.Import {
+			if existing.Package == rootPkg {
+				pkg = existing
+				break
+			}
+		}
+		if version != "" {
+			pkg.Version = version
+		}
+		if remote != "" {
+			pkg.Remote = remote
+		}
+		if subPkg != "" && !download.ContainsString(pkg.Subpackages, subPkg) {
+			pkg.Subpackages = append(append([]string{}, pkg.Subpackages...), subPkg)
+		}
+
+		logger.Printf("vendoring %s", pkg.Package)
+		lp, err := p.Download(pkg)
+		if err != nil {
+			return fmt.Errorf("download package %s: %v", pkg.Package, err)
+		}
+
+		err = p.UpdateManifest(func(m *download.Manifest) error {
+			setManifestPackage(m, pkg)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		setManifestPackage(m, pkg)
+
+		if err := setLockPackage(p, lp); err != nil {
+			return err
+		}
+		logger.Printf("locked %s at version %s", lp.Package, lp.Version)
+	}
+	return nil
+}
+
+// setManifestPackage adds the package to the manifest, replacing any existing
+// entry with the same name. New entries are appended to preserve the order
+// of the file.
+func setManifestPackage(m *download.Manifest, pkg download.ManifestPackage) {
+	for i, existing := range m.Import {
+		if existing.Package == pkg.Package {
+			m.Import[i] = pkg
+			return
+		}
+	}
+	m.Import = append(m.Import, pkg)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import "testing"
+
+func TestParsePackageArg(t *testing.T) {
+	tests := []struct {
+		arg                      string
+		rootPkg, subPkg, version string
+		wantErr                  bool
+	}{
+		{arg: "github.com/foo/bar", rootPkg: "github.com/foo/bar"},
+		{arg: "github.com/foo/bar/", rootPkg: "github.com/foo/bar"},
+		{arg: "github.com/foo/bar@v1.2.3", rootPkg: "github.com/foo/bar", version: "v1.2.3"},
+		{arg: "github.com/foo/bar/baz/qux@abcdef", rootPkg: "github.com/foo/bar", subPkg: "baz/qux", version: "abcdef"},
+		{arg: "github.com/foo/bar@", wantErr: true},
+		{arg: "@v1.2.3", wantErr: true},
+	}
+	for _, test := range tests {
+		rootPkg, subPkg, version, err := parsePackageArg(test.arg)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("parsePackageArg(%q): %v", test.arg, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("parsePackageArg(%q): expected error", test.arg)
+			continue
+		}
+		if rootPkg != test.rootPkg || subPkg != test.subPkg || version != test.version {
+			t.Errorf("parsePackageArg(%q), want=(%q, %q, %q), got=(%q, %q, %q)", test.arg,
+				test.rootPkg, test.subPkg, test.version, rootPkg, subPkg, version)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// Values of the graph command's --format and --level flags.
+const (
+	graphDOT     = "dot"
+	graphJSON    = "json"
+	levelPackage = "package"
+	levelRepo    = "repo"
+)
+
+// graphOptions configures the import graph printed by the graph command.
+type graphOptions struct {
+	format string
+	level  string
+	// roots limits the graph to what these packages import.
+	roots []string
+}
+
+// importGraph is the import graph of a project and its vendored packages.
+type importGraph struct {
+	imports download.ImportGraph
+	// project holds the nodes that belong to the project.
+	project map[string]bool
+}
+
+type graphNode struct {
+	Name    string `json:"name"`
+	Project bool   `json:"project"`
+}
+
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// printGraph writes the import graph between the project's packages and the
+// vendored packages.
+func printGraph(p *download.Project, out io.Writer, opts graphOptions) error {
+	if opts.format != graphDOT && opts.format != graphJSON {
+		return fmt.Errorf("invalid --format value %q, must be %q or %q", opts.format, graphDOT, graphJSON)
+	}
+	if opts.level != levelPackage && opts.level != levelRepo {
+		return fmt.Errorf("invalid --level value %q, must be %q or %q", opts.level, levelPackage, levelRepo)
+	}
+
+	g := importGraph{imports: make(download.ImportGraph), project: make(map[string]bool)}
+	vendorGraph, err := p.VendorImports()
+	if err != nil {
+		return err
+	}
+	for pkg, imports := range vendorGraph {
+		g.imports[pkg] = imports
+	}
+	projectGraph, err := p.ProjectGraph()
+	if err != nil {
+		return err
+	}
+	for pkg, imports := range projectGraph {
+		g.imports[pkg] = imports
+		g.project[pkg] = true
+	}
+
+	// Filter before grouping by repo so roots can name project packages.
+	if len(opts.roots) > 0 {
+		if g, err = g.reachable(opts.roots); err != nil {
+			return err
+		}
+	}
+	if opts.level == levelRepo {
+		l, err := p.LoadLock()
+		if err != nil {
+			return err
+		}
+		var projectPath string
+		if len(projectGraph) > 0 {
+			if projectPath, err = p.ImportPath(); err != nil {
+				return err
+			}
+		}
+		g = g.repos(l, projectPath)
+	}
+
+	if opts.format == graphJSON {
+		return g.writeJSON(out)
+	}
+	return g.writeDOT(out)
+}
+
+// repos collapses the graph so each node is a repo: a package of the lock file,
+// or the project itself.
+func (g importGraph) repos(l *download.Lock, projectPath string) importGraph {
+	repoOf := func(pkg string) string {
+		if g.project[pkg] {
+			return projectPath
+		}
+		repo := ""
+		for _, lockPkg := range l.Import {
+			if download.InPackage(pkg, lockPkg.Package) && len(lockPkg.Package) > len(repo) {
+				repo = lockPkg.Package
+			}
+		}
+		if repo == "" {
+			return pkg
+		}
+		return repo
+	}
+
+	repos := importGraph{imports: make(download.ImportGraph), project: make(map[string]bool)}
+	for pkg, imports := range g.imports {
+		from := repoOf(pkg)
+		if _, ok := repos.imports[from]; !ok {
+			repos.imports[from] = nil
+		}
+		repos.project[from] = g.project[pkg]
+		for _, importPath := range imports {
+			to := repoOf(importPath)
+			if to != from && !download.ContainsString(repos.imports[from], to) {
+				repos.imports[from] = append(repos.imports[from], to)
+			}
+		}
+	}
+	for _, imports := range repos.imports {
+		sort.Strings(imports)
+	}
+	return repos
+}
+
+// reachable returns the part of the graph imported, directly or not, by the
+// root packages. A root matches a node and all the nodes within it.
+func (g importGraph) reachable(roots []string) (importGraph, error) {
+	sub := importGraph{imports: make(download.ImportGraph), project: make(map[string]bool)}
+	var visit func(pkg string)
+	visit = func(pkg string) {
+		if _, ok := sub.imports[pkg]; ok {
+			return
+		}
+		sub.imports[pkg] = g.imports[pkg]
+		sub.project[pkg] = g.project[pkg]
+		for _, importPath := range g.imports[pkg] {
+			visit(importPath)
+		}
+	}
+	for _, root := range roots {
+		found := false
+		for pkg := range g.imports {
+			if download.InPackage(pkg, root) {
+				visit(pkg)
+				found = true
+			}
+		}
+		if !found {
+			return importGraph{}, fmt.Errorf("root package %s not found in the import graph", root)
+		}
+	}
+	return sub, nil
+}
+
+func (g importGraph) nodes() []string {
+	var nodes []string
+	for pkg := range g.imports {
+		nodes = append(nodes, pkg)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (g importGraph) writeDOT(w io.Writer) error {
+	fmt.Fprintln(w, "digraph imports {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	for _, pkg := range g.nodes() {
+		if g.project[pkg] {
+			fmt.Fprintf(w, "\t%s [shape=box];\n", strconv.Quote(pkg))
+		} else {
+			fmt.Fprintf(w, "\t%s;\n", strconv.Quote(pkg))
+		}
+	}
+	for _, pkg := range g.nodes() {
+		for _, importPath := range g.imports[pkg] {
+			fmt.Fprintf(w, "\t%s -> %s;\n", strconv.Quote(pkg), strconv.Quote(importPath))
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+func (g importGraph) writeJSON(w io.Writer) error {
+	v := struct {
+		Nodes []graphNode `json:"nodes"`
+		Edges []graphEdge `json:"edges"`
+	}{Nodes: []graphNode{}, Edges: []graphEdge{}}
+	for _, pkg := range g.nodes() {
+		v.Nodes = append(v.Nodes, graphNode{Name: pkg, Project: g.project[pkg]})
+		for _, importPath := range g.imports[pkg] {
+			v.Edges = append(v.Edges, graphEdge{From: pkg, To: importPath})
+		}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
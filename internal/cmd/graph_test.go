@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+func TestImportGraph(t *testing.T) {
+	g := importGraph{
+		imports: download.ImportGraph{
+			"example.com/app":         {"example.com/app/lib", "github.com/a/b"},
+			"example.com/app/lib":     {"github.com/c/d/e"},
+			"example.com/app/tool":    {"github.com/f/g"},
+			"github.com/a/b":          {"github.com/a/b/internal"},
+			"github.com/a/b/internal": {"github.com/c/d"},
+			"github.com/c/d":          nil,
+			"github.com/c/d/e":        {"github.com/c/d"},
+			"github.com/f/g":          nil,
+		},
+		project: map[string]bool{
+			"example.com/app":      true,
+			"example.com/app/lib":  true,
+			"example.com/app/tool": true,
+		},
+	}
+	l := &download.Lock{Import: []download.LockPackage{
+		{Package: "github.com/a/b"},
+		{Package: "github.com/c/d"},
+		{Package: "github.com/f/g"},
+	}}
+
+	repos := g.repos(l, "example.com/app")
+	wantRepos := download.ImportGraph{
+		"example.com/app": {"github.com/a/b", "github.com/c/d", "github.com/f/g"},
+		"github.com/a/b":  {"github.com/c/d"},
+		"github.com/c/d":  nil,
+		"github.com/f/g":  nil,
+	}
+	if !reflect.DeepEqual(repos.imports, wantRepos) {
+		t.Errorf("repos: want=%q, got=%q", wantRepos, repos.imports)
+	}
+	if !repos.project["example.com/app"] || repos.project["github.com/a/b"] {
+		t.Errorf("repos: unexpected project nodes %v", repos.project)
+	}
+
+	sub, err := g.reachable([]string{"example.com/app/lib"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSub := download.ImportGraph{
+		"example.com/app/lib": {"github.com/c/d/e"},
+		"github.com/c/d":      nil,
+		"github.com/c/d/e":    {"github.com/c/d"},
+	}
+	if !reflect.DeepEqual(sub.imports, wantSub) {
+		t.Errorf("reachable: want=%q, got=%q", wantSub, sub.imports)
+	}
+	// Roots name packages, so repos are grouped after filtering.
+	sub, err = g.reachable([]string{"example.com/app/tool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSubRepos := download.ImportGraph{
+		"example.com/app": {"github.com/f/g"},
+		"github.com/f/g":  nil,
+	}
+	if got := sub.repos(l, "example.com/app").imports; !reflect.DeepEqual(got, wantSubRepos) {
+		t.Errorf("reachable repos: want=%q, got=%q", wantSubRepos, got)
+	}
+
+	if _, err := g.reachable([]string{"github.com/x/y"}); err == nil {
+		t.Errorf("expected error for unknown root")
+	}
+}
@@ -1,77 +1,133 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
-	"strings"
+	"text/tabwriter"
 
 	"github.com/ericchiang/godl/internal/download"
-	"github.com/ericchiang/godl/internal/forked/glideutil"
+	"github.com/ericchiang/godl/internal/importer"
 )
 
-func importManifest(p *download.Project, logger *log.Logger, manifest string) error {
-	data, err := ioutil.ReadFile(manifest)
-	if err != nil {
-		return fmt.Errorf("read manifest: %v", err)
+// Values of the import command's --prefer flag.
+const (
+	preferExisting = "existing"
+	preferImported = "imported"
+)
+
+// importOptions configures how another tool's manifest is imported.
+type importOptions struct {
+	// merge adds the imported packages to an existing manifest.
+	merge bool
+	// prefer picks the side that wins version conflicts when merging.
+	prefer string
+}
+
+// importManifest converts the manifest of another tool into the project's
+// manifest. If no manifest is provided, the project directory is searched for
+// the most precise one.
+func importManifest(p *download.Project, logger *log.Logger, out io.Writer, manifest string, opts importOptions) error {
+	if opts.prefer != preferExisting && opts.prefer != preferImported {
+		return fmt.Errorf("invalid --prefer value %q, must be %q or %q", opts.prefer, preferExisting, preferImported)
 	}
-	var godeps struct {
-		Deps []struct {
-			ImportPath string
-			Rev        string
-			Comment    string
+
+	var (
+		i   importer.Importer
+		err error
+	)
+	if manifest == "" {
+		if i, manifest, err = importer.Detect(p.Dir); err != nil {
+			return err
 		}
+	} else if i, err = importer.ForFile(manifest); err != nil {
+		return err
 	}
-	if err := json.Unmarshal(data, &godeps); err != nil {
-		return fmt.Errorf("parsing manifest: %v", err)
+	logger.Printf("importing %s from %s", i.Name(), manifest)
+
+	pkgs, err := i.Import(p, manifest, logger)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		logger.Printf("found dependency %s at version %s", pkg.Package, displayVersion(pkg.Version))
+	}
+	if !opts.merge {
+		return p.Import(&download.Manifest{Import: pkgs})
 	}
 
-	var pkgs []download.ManifestPackage
-	for _, dep := range godeps.Deps {
-		rootPkg, err := glideutil.GetRootFromPackage(dep.ImportPath)
-		if err != nil {
-			return err
-		}
+	var conflicts []mergeConflict
+	err = p.UpdateManifest(func(m *download.Manifest) error {
+		conflicts = mergeManifest(m, pkgs, opts.prefer == preferImported)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
 
-		subPkg := strings.TrimPrefix(strings.TrimPrefix(dep.ImportPath, rootPkg), "/")
+	logger.Printf("resolved %d conflicts using %s versions", len(conflicts), opts.prefer)
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tEXISTING\tIMPORTED\tCHOSEN")
+	for _, c := range conflicts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.existing.Package,
+			describePin(c.existing), describePin(c.imported), describePin(c.chosen))
+	}
+	return tw.Flush()
+}
 
-		found := false
-		for i, pkg := range pkgs {
-			if pkg.Package != rootPkg {
-				continue
-			}
+// mergeConflict is a package whose version or remote differs between an
+// existing manifest and an imported one.
+type mergeConflict struct {
+	existing download.ManifestPackage
+	imported download.ManifestPackage
+	chosen   download.ManifestPackage
+}
 
-			found = true
-			if subPkg == "" {
-				// Root package, nothing to do.
+// mergeManifest adds imported packages to a manifest. Packages in both have
+// their subpackages combined, and conflicting versions and remotes are taken
+// from the existing manifest unless preferImported is set.
+func mergeManifest(m *download.Manifest, imported []download.ManifestPackage, preferImported bool) []mergeConflict {
+	var conflicts []mergeConflict
+	for _, pkg := range imported {
+		i := -1
+		for j, existing := range m.Import {
+			if existing.Package == pkg.Package {
+				i = j
 				break
 			}
-
-			pkgs[i].Subpackages = append(pkg.Subpackages, subPkg)
 		}
-
-		if found {
+		if i < 0 {
+			m.Import = append(m.Import, pkg)
 			continue
 		}
 
-		version := dep.Rev
-		if strings.HasPrefix(dep.Comment, "v") {
-			// Comment looks like a version tag.
-			version = dep.Comment
-		}
-		pkg := download.ManifestPackage{
-			Package: rootPkg,
-			Version: version,
+		existing := m.Import[i]
+		chosen := existing
+		if preferImported {
+			chosen.Version, chosen.Remote = pkg.Version, pkg.Remote
 		}
-		if subPkg != "" {
-			pkg.Subpackages = []string{subPkg}
+		chosen.Subpackages = append([]string(nil), existing.Subpackages...)
+		for _, subPkg := range pkg.Subpackages {
+			if !download.ContainsString(chosen.Subpackages, subPkg) {
+				chosen.Subpackages = append(chosen.Subpackages, subPkg)
+			}
 		}
+		m.Import[i] = chosen
 
-		logger.Printf("found dependency %s at version %s", pkg.Package, pkg.Version)
-
-		pkgs = append(pkgs, pkg)
+		if existing.Version != pkg.Version || existing.Remote != pkg.Remote {
+			conflicts = append(conflicts, mergeConflict{existing, pkg, chosen})
+		}
 	}
+	return conflicts
+}
 
-	return p.Import(&download.Manifest{Import: pkgs})
+// describePin formats the version and remote of a package.
+func describePin(pkg download.ManifestPackage) string {
+	if pkg.Remote == "" {
+		return displayVersion(pkg.Version)
+	}
+	return displayVersion(pkg.Version) + " (" + pkg.Remote + ")"
 }
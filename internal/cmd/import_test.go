@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+func TestMergeManifest(t *testing.T) {
+	existing := func() *download.Manifest {
+		return &download.Manifest{
+			Import: []download.ManifestPackage{
+				{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"x"}},
+				{Package: "github.com/a/conflict", Version: "v1.0.0"},
+			},
+		}
+	}
+	imported := []download.ManifestPackage{
+		{Package: "github.com/a/conflict", Version: "v2.0.0", Remote: "git@github.com:a/conflict.git"},
+		{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"y", "x"}},
+		{Package: "github.com/a/new", Version: "v0.1.0"},
+	}
+
+	tests := []struct {
+		preferImported bool
+		want           []download.ManifestPackage
+	}{
+		{
+			preferImported: false,
+			want: []download.ManifestPackage{
+				{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"x", "y"}},
+				{Package: "github.com/a/conflict", Version: "v1.0.0"},
+				{Package: "github.com/a/new", Version: "v0.1.0"},
+			},
+		},
+		{
+			preferImported: true,
+			want: []download.ManifestPackage{
+				{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"x", "y"}},
+				{
+					Package: "github.com/a/conflict",
+					Version: "v2.0.0",
+					Remote:  "git@github.com:a/conflict.git",
+				},
+				{Package: "github.com/a/new", Version: "v0.1.0"},
+			},
+		},
+	}
+	for _, test := range tests {
+		m := existing()
+		conflicts := mergeManifest(m, imported, test.preferImported)
+		if !reflect.DeepEqual(m.Import, test.want) {
+			t.Errorf("preferImported=%t, expected manifest %#v got %#v", test.preferImported, test.want, m.Import)
+		}
+		if len(conflicts) != 1 || conflicts[0].existing.Package != "github.com/a/conflict" {
+			t.Errorf("preferImported=%t, expected one conflict for github.com/a/conflict got %#v", test.preferImported, conflicts)
+		}
+	}
+}
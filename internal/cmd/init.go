@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/forked/glideutil"
+)
+
+// initProject creates empty manifest and lock files. If fromVendor is set, the
+// files instead describe the packages already present in the vendor directory.
+func initProject(p *download.Project, logger *log.Logger, fromVendor bool) error {
+	m := new(download.Manifest)
+	l := &download.Lock{Import: []download.LockPackage{}}
+	if fromVendor {
+		pkgs, err := vendoredRootPackages(p)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			logger.Printf("found vendored package %s, no version known (pin it with 'godl get %s@<version>')", pkg.Package, pkg.Package)
+			m.Import = append(m.Import, pkg)
+			l.Import = append(l.Import, download.LockPackage{
+				Package:     pkg.Package,
+				Version:     pkg.Version,
+				Remote:      pkg.Remote,
+				Subpackages: pkg.Subpackages,
+			})
+		}
+	}
+	return p.Init(m, l)
+}
+
+// vendoredRootPackages groups the packages in the vendor directory by the root
+// of their repo.
+func vendoredRootPackages(p *download.Project) ([]download.ManifestPackage, error) {
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, importPath := range vendored {
+		// Avoid looking up roots we already know, which may require network requests.
+		found := false
+		for i, pkg := range pkgs {
+			if !download.InPackage(importPath, pkg.Package) {
+				continue
+			}
+			found = true
+			if importPath != pkg.Package {
+				pkgs[i].Subpackages = append(pkg.Subpackages, strings.TrimPrefix(importPath, pkg.Package+"/"))
+			}
+			break
+		}
+		if found {
+			continue
+		}
+
+		rootPkg, err := glideutil.GetRootFromPackage(importPath)
+		if err != nil {
+			return nil, err
+		}
+		pkg := download.ManifestPackage{Package: rootPkg}
+		if subPkg := strings.TrimPrefix(strings.TrimPrefix(importPath, rootPkg), "/"); subPkg != "" {
+			pkg.Subpackages = []string{subPkg}
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
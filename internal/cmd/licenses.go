@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// packageLicenses is the license inventory of a locked package.
+type packageLicenses struct {
+	Package  string                 `json:"package"`
+	Licenses []string               `json:"licenses"`
+	Files    []download.LicenseFile `json:"files"`
+	// Violation describes how the package violates the manifest's license
+	// policy, if it does.
+	Violation string `json:"violation,omitempty"`
+}
+
+// licenseInventory classifies the license files of every package in the lock
+// file, checking each against the policy if one is provided.
+func licenseInventory(p *download.Project, policy *download.LicensePolicy) ([]packageLicenses, error) {
+	l, err := p.LoadLock()
+	if err != nil {
+		return nil, err
+	}
+	inventory := []packageLicenses{}
+	for _, pkg := range l.Import {
+		files, err := p.LicenseFiles(pkg.Package)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("package %s is missing from the vendor directory, run 'godl vendor'", pkg.Package)
+			}
+			return nil, fmt.Errorf("package %s: %v", pkg.Package, err)
+		}
+		pl := packageLicenses{
+			Package:  pkg.Package,
+			Licenses: download.LicenseIDs(files),
+			Files:    files,
+		}
+		if policy != nil {
+			if err := policy.Check(files); err != nil {
+				pl.Violation = err.Error()
+			}
+		}
+		inventory = append(inventory, pl)
+	}
+	return inventory, nil
+}
+
+// reportLicenses prints the licenses of every package in the lock file. If the
+// manifest has a license policy, packages violating it are reported and an
+// error is returned.
+func reportLicenses(p *download.Project, out io.Writer, asJSON bool) error {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return err
+	}
+	inventory, err := licenseInventory(p, m.Licenses)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		e := json.NewEncoder(out)
+		e.SetIndent("", "  ")
+		if err := e.Encode(inventory); err != nil {
+			return err
+		}
+	} else {
+		tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		header := "PACKAGE\tLICENSE\tFILES"
+		if m.Licenses != nil {
+			header += "\tPOLICY"
+		}
+		fmt.Fprintln(tw, header)
+		for _, pl := range inventory {
+			var paths []string
+			for _, f := range pl.Files {
+				paths = append(paths, f.Path)
+			}
+			row := fmt.Sprintf("%s\t%s\t%s", pl.Package, displayLicenses(pl), displayList(paths))
+			if m.Licenses != nil {
+				status := "ok"
+				if pl.Violation != "" {
+					status = pl.Violation
+				}
+				row += "\t" + status
+			}
+			fmt.Fprintln(tw, row)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return policyViolations(inventory)
+}
+
+// checkLicensePolicy verifies the vendored packages against the manifest's
+// license policy, if it has one.
+func checkLicensePolicy(p *download.Project, logger *log.Logger) error {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return err
+	}
+	if m.Licenses == nil {
+		return nil
+	}
+	inventory, err := licenseInventory(p, m.Licenses)
+	if err != nil {
+		return err
+	}
+	for _, pl := range inventory {
+		if pl.Violation != "" {
+			logger.Printf("license policy: %s: %s", pl.Package, pl.Violation)
+		}
+	}
+	return policyViolations(inventory)
+}
+
+func policyViolations(inventory []packageLicenses) error {
+	n := 0
+	for _, pl := range inventory {
+		if pl.Violation != "" {
+			n++
+		}
+	}
+	if n > 0 {
+		return fmt.Errorf("%d of %d packages violate the license policy", n, len(inventory))
+	}
+	return nil
+}
+
+func displayLicenses(pl packageLicenses) string {
+	switch {
+	case len(pl.Licenses) > 0:
+		return strings.Join(pl.Licenses, ", ")
+	case len(pl.Files) > 0:
+		return "unknown"
+	}
+	return "none"
+}
+
+func displayList(s []string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// listedPackage is the information printed for each package by the list
+// command. Its fields are available to format templates.
+type listedPackage struct {
+	Package     string
+	Version     string
+	Revision    string
+	Remote      string
+	Subpackages []string
+	Hash        string
+	// Dir is the package's directory in the vendor directory.
+	Dir string
+
+	InManifest bool
+	InLock     bool
+}
+
+// listPackages prints the packages in the lock file, along with packages only
+// present in the manifest. Packages present in the lock file use its version,
+// remote and subpackages.
+func listPackages(p *download.Project, out io.Writer, format string, asJSON, mismatched bool) error {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return err
+	}
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	var tmpl *template.Template
+	if format != "" {
+		if tmpl, err = template.New("format").Funcs(template.FuncMap{"join": strings.Join}).Parse(format); err != nil {
+			return fmt.Errorf("parsing format: %v", err)
+		}
+	}
+
+	pkgs := make(map[string]*listedPackage)
+	for _, pkg := range l.Import {
+		pkgs[pkg.Package] = &listedPackage{
+			Package:     pkg.Package,
+			Version:     pkg.Version,
+			Revision:    pkg.Rev(),
+			Remote:      pkg.Remote,
+			Subpackages: pkg.Subpackages,
+			Hash:        pkg.Hash,
+			Dir:         p.PackageDir(pkg.Package),
+			InLock:      true,
+		}
+	}
+	for _, pkg := range m.Import {
+		if lp, ok := pkgs[pkg.Package]; ok {
+			lp.InManifest = true
+			continue
+		}
+		pkgs[pkg.Package] = &listedPackage{
+			Package:     pkg.Package,
+			Version:     pkg.Version,
+			Remote:      pkg.Remote,
+			Subpackages: pkg.Subpackages,
+			Dir:         p.PackageDir(pkg.Package),
+			InManifest:  true,
+		}
+	}
+
+	var names []string
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pkg := pkgs[name]
+		if mismatched && pkg.InManifest && pkg.InLock {
+			continue
+		}
+
+		switch {
+		case asJSON:
+			data, err := json.MarshalIndent(pkg, "", "\t")
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, "%s\n", data); err != nil {
+				return err
+			}
+		case tmpl != nil:
+			if err := tmpl.Execute(out, pkg); err != nil {
+				return fmt.Errorf("executing format: %v", err)
+			}
+			if _, err := fmt.Fprintln(out); err != nil {
+				return err
+			}
+		default:
+			line := pkg.Package + " " + displayVersion(pkg.Version)
+			if !pkg.InLock {
+				line += " (not in lock file)"
+			} else if !pkg.InManifest {
+				line += " (not in manifest)"
+			}
+			if _, err := fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
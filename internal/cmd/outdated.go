@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// reportOutdated compares every package in the lock file against its remote
+// and writes the results as a table or JSON.
+func reportOutdated(p *download.Project, logger *log.Logger, out io.Writer, asJSON bool) error {
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	results := []*download.Outdated{}
+	for _, pkg := range l.Import {
+		logger.Printf("checking %s", pkg.Package)
+		o, err := p.Outdated(pkg)
+		if err != nil {
+			return fmt.Errorf("check package %s: %v", pkg.Package, err)
+		}
+		results = append(results, o)
+	}
+
+	if asJSON {
+		e := json.NewEncoder(out)
+		e.SetIndent("", "  ")
+		return e.Encode(results)
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tVERSION\tAGE\tLATEST TAG\tBEHIND")
+	for _, o := range results {
+		latest := o.LatestTag
+		if latest == "" {
+			latest = "-"
+		}
+		behind := "?"
+		if o.CommitsBehind >= 0 {
+			behind = strconv.Itoa(o.CommitsBehind)
+			if o.DefaultBranch != "" {
+				behind += " (" + o.DefaultBranch + ")"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			o.Package, o.Version, age(now.Sub(o.RevisionDate)), latest, behind)
+	}
+	return tw.Flush()
+}
+
+// age formats a duration in whole days.
+func age(d time.Duration) string {
+	return strconv.Itoa(int(d.Hours()/24)) + "d"
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// removePackages deletes packages from the manifest, the lock file and the vendor
+// directory. Unless force is set, packages still imported by the project are
+// refused.
+func removePackages(p *download.Project, logger *log.Logger, pkgs []string, force bool) error {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return err
+	}
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for _, pkg := range m.Import {
+		known[pkg.Package] = true
+	}
+	for _, pkg := range l.Import {
+		known[pkg.Package] = true
+	}
+	for _, pkg := range pkgs {
+		if !known[pkg] {
+			return fmt.Errorf("package %s is not in the manifest or lock file", pkg)
+		}
+	}
+
+	files, err := p.ProjectImports()
+	if err != nil {
+		return fmt.Errorf("determining project imports: %v", err)
+	}
+	for _, pkg := range pkgs {
+		importedBy := importingFiles(files, pkg)
+		if len(importedBy) == 0 {
+			continue
+		}
+		if !force {
+			return fmt.Errorf("package %s is still imported by %s (use --force to remove it anyway)",
+				pkg, strings.Join(importedBy, ", "))
+		}
+		logger.Printf("warning: package %s is still imported by %s", pkg, strings.Join(importedBy, ", "))
+	}
+
+	remove := make(map[string]bool)
+	for _, pkg := range pkgs {
+		remove[pkg] = true
+	}
+
+	err = p.UpdateManifest(func(m *download.Manifest) error {
+		var keep []download.ManifestPackage
+		for _, pkg := range m.Import {
+			if !remove[pkg.Package] {
+				keep = append(keep, pkg)
+			}
+		}
+		m.Import = keep
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.UpdateLock(func(l *download.Lock) error {
+		keep := []download.LockPackage{}
+		for _, pkg := range l.Import {
+			if !remove[pkg.Package] {
+				keep = append(keep, pkg)
+			}
+		}
+		l.Import = keep
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		logger.Printf("removing %s", pkg)
+		if err := p.Remove(pkg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importingFiles returns the sorted list of files that import pkg or one of its
+// subpackages.
+func importingFiles(files map[string][]string, pkg string) []string {
+	var importedBy []string
+	for file, imports := range files {
+		for _, importPath := range imports {
+			if download.InPackage(importPath, pkg) {
+				importedBy = append(importedBy, file)
+				break
+			}
+		}
+	}
+	sort.Strings(importedBy)
+	return importedBy
+}
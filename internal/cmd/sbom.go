@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/sbom"
+)
+
+// writeSBOM writes a software bill of materials for the project's vendored
+// dependencies.
+func writeSBOM(p *download.Project, logger *log.Logger, out io.Writer, format string) error {
+	switch format {
+	case "spdx-json", "cyclonedx-json", "cyclonedx-xml":
+	default:
+		return fmt.Errorf("unsupported format %q, supported formats are spdx-json, cyclonedx-json and cyclonedx-xml", format)
+	}
+	b, err := sbom.Collect(p, logger)
+	if err != nil {
+		return err
+	}
+	if format == "spdx-json" {
+		return sbom.WriteSPDX(out, b)
+	}
+	return sbom.WriteCycloneDX(out, b, format == "cyclonedx-xml")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// updatePackages re-resolves floating packages, those with no version or a
+// branch as their version, and re-vendors any that moved. If no packages are
+// passed, every package in the manifest is considered.
+func updatePackages(p *download.Project, logger *log.Logger, out io.Writer, pkgs []string) error {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return err
+	}
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	locked := make(map[string]download.LockPackage)
+	for _, pkg := range l.Import {
+		locked[pkg.Package] = pkg
+	}
+
+	toUpdate := m.Import
+	if len(pkgs) > 0 {
+		toUpdate = nil
+		for _, name := range pkgs {
+			found := false
+			for _, pkg := range m.Import {
+				if pkg.Package == name {
+					toUpdate = append(toUpdate, pkg)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("package %s is not in the manifest", name)
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	updated := 0
+	for _, pkg := range toUpdate {
+		lockPkg, ok := locked[pkg.Package]
+		if !ok {
+			logger.Printf("skipping %s: not vendored yet, run 'godl vendor'", pkg.Package)
+			continue
+		}
+
+		logger.Printf("resolving %s", pkg.Package)
+		rev, floating, err := p.Resolve(pkg)
+		if err != nil {
+			return fmt.Errorf("resolve package %s: %v", pkg.Package, err)
+		}
+		if !floating {
+			if len(pkgs) > 0 {
+				logger.Printf("skipping %s: pinned to %s", pkg.Package, pkg.Version)
+			}
+			continue
+		}
+		if rev == lockPkg.Rev() {
+			continue
+		}
+
+		logger.Printf("vendoring %s", pkg.Package)
+		lp, err := p.Download(pkg)
+		if err != nil {
+			return fmt.Errorf("download package %s: %v", pkg.Package, err)
+		}
+		if err := setLockPackage(p, lp); err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "%s\t%s\t->\t%s\n", pkg.Package, lockPkg.Rev(), lp.Rev())
+		updated++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		logger.Printf("dependencies up to date")
+	}
+	return nil
+}
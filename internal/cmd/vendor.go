@@ -2,90 +2,133 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
-	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/ericchiang/godl/internal/download"
 )
 
-func downloadAll(p *download.Project, logger *log.Logger) error {
-	m, err := p.LoadManifest()
+func downloadAll(p *download.Project, logger *log.Logger, out io.Writer, dryRun bool) error {
+	plan, err := p.Plan()
 	if err != nil {
 		return err
 	}
-	l, err := p.LoadLock()
-	if err != nil {
-		return err
+	if dryRun {
+		return printPlan(out, plan)
 	}
 
-	downloaded := make(map[string]download.LockPackage)
-	for _, pkg := range l.Import {
-		downloaded[pkg.Package] = pkg
+	if len(plan.Changes) == 0 {
+		logger.Printf("dependencies up to date")
 	}
 
-	didSomething := false
-
-	inManifest := make(map[string]struct{})
-	for _, pkg := range m.Import {
-		inManifest[pkg.Package] = struct{}{}
-
-		lockPkg, ok := downloaded[pkg.Package]
-		if ok && packagesEq(lockPkg, pkg) {
+	for _, c := range plan.Changes {
+		if c.Action == download.Remove {
+			logger.Printf("removing %s", c.Package)
+			if err := p.Remove(c.Package); err != nil {
+				return err
+			}
+			if err := removeLockPackage(p, c.Package); err != nil {
+				return err
+			}
 			continue
 		}
-		didSomething = true
 
-		logger.Printf("vendoring %s", pkg.Package)
-		lp, err := p.Download(pkg)
+		logger.Printf("vendoring %s", c.Package)
+		lp, err := p.Download(*c.Manifest)
 		if err != nil {
-			return fmt.Errorf("download package %s: %v", pkg.Package, err)
+			return fmt.Errorf("download package %s: %v", c.Package, err)
 		}
-		err = p.UpdateLock(func(l *download.Lock) error {
-			for i, lockPkg := range l.Import {
-				if lockPkg.Package == lp.Package {
-					l.Import[i] = lp
-					return nil
-				}
-			}
-			l.Import = append(l.Import, lp)
-			return nil
-		})
-		if err != nil {
+		if err := setLockPackage(p, lp); err != nil {
 			return err
 		}
 	}
+	return checkLicensePolicy(p, logger)
+}
 
-	for _, pkg := range l.Import {
-		if _, ok := inManifest[pkg.Package]; !ok {
-			didSomething = true
-			logger.Printf("removing %s", pkg.Package)
-			if err := p.Remove(pkg.Package); err != nil {
-				return err
-			}
-		}
+// printPlan writes a table describing each change of a plan.
+func printPlan(w io.Writer, plan *download.Plan) error {
+	if len(plan.Changes) == 0 && len(plan.Untracked) == 0 {
+		_, err := fmt.Fprintln(w, "dependencies up to date")
+		return err
 	}
 
-	if !didSomething {
-		logger.Printf("dependencies up to date")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, c := range plan.Changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Action, c.Package, describeChange(c))
+	}
+	for _, pkg := range plan.Untracked {
+		fmt.Fprintf(tw, "untracked\t%s\tnot in lock file\n", pkg)
+	}
+	return tw.Flush()
+}
+
+func describeChange(c download.Change) string {
+	switch c.Action {
+	case download.Add:
+		return "version " + displayVersion(c.Manifest.Version)
+	case download.Remove:
+		return "not in manifest"
+	case download.Restore:
+		return "missing from vendor directory"
 	}
 
-	return nil
+	var diffs []string
+	for _, field := range c.Fields {
+		switch field {
+		case download.FieldVersion:
+			diffs = append(diffs, fmt.Sprintf("version %s -> %s",
+				displayVersion(c.Lock.Version), displayVersion(c.Manifest.Version)))
+		case download.FieldRemote:
+			diffs = append(diffs, fmt.Sprintf("remote %s -> %s",
+				displayRemote(c.Lock.Remote), displayRemote(c.Manifest.Remote)))
+		case download.FieldSubpackages:
+			diffs = append(diffs, fmt.Sprintf("subpackages [%s] -> [%s]",
+				strings.Join(c.Lock.Subpackages, " "), strings.Join(c.Manifest.Subpackages, " ")))
+		}
+	}
+	return strings.Join(diffs, ", ")
 }
 
-func packagesEq(l download.LockPackage, m download.ManifestPackage) bool {
-	if l.Package != m.Package ||
-		l.Version != m.Version ||
-		l.Remote != m.Remote ||
-		len(l.Subpackages) != len(m.Subpackages) {
+func displayVersion(v string) string {
+	if v == "" {
+		return "latest"
+	}
+	return v
+}
 
-		return false
+func displayRemote(r string) string {
+	if r == "" {
+		return "default"
 	}
-	sort.Strings(l.Subpackages)
-	sort.Strings(m.Subpackages)
-	for i, p := range l.Subpackages {
-		if m.Subpackages[i] != p {
-			return false
+	return r
+}
+
+// setLockPackage adds the package to the lock file, replacing any existing
+// entry with the same name.
+func setLockPackage(p *download.Project, lp download.LockPackage) error {
+	return p.UpdateLock(func(l *download.Lock) error {
+		for i, lockPkg := range l.Import {
+			if lockPkg.Package == lp.Package {
+				l.Import[i] = lp
+				return nil
+			}
 		}
-	}
-	return true
+		l.Import = append(l.Import, lp)
+		return nil
+	})
+}
+
+// removeLockPackage deletes the package from the lock file.
+func removeLockPackage(p *download.Project, pkg string) error {
+	return p.UpdateLock(func(l *download.Lock) error {
+		for i, lockPkg := range l.Import {
+			if lockPkg.Package == pkg {
+				l.Import = append(l.Import[:i], l.Import[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// verifyVendor recomputes the hash of every package in the vendor directory and
+// compares it against the lock file. An error is returned if any package is
+// missing, was modified or has no hash recorded.
+func verifyVendor(p *download.Project, logger *log.Logger, out io.Writer) error {
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	verified, failed := 0, 0
+	for _, pkg := range l.Import {
+		if pkg.Hash == "" {
+			fmt.Fprintf(tw, "%s\tno hash recorded, re-download it to record one\n", pkg.Package)
+			failed++
+			continue
+		}
+		got, err := p.HashPackage(pkg.Package)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("hash package %s: %v", pkg.Package, err)
+			}
+			fmt.Fprintf(tw, "%s\tmissing from vendor directory\n", pkg.Package)
+			failed++
+			continue
+		}
+		if got != pkg.Hash {
+			fmt.Fprintf(tw, "%s\tmodified\twant %s\tgot %s\n", pkg.Package, pkg.Hash, got)
+			failed++
+			continue
+		}
+		verified++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d packages failed verification", failed, len(l.Import))
+	}
+	logger.Printf("verified %d packages", verified)
+	return nil
+}
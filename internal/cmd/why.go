@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// explainImport prints the shortest import chains from the project's main and
+// top-level packages to a vendored package, followed by the packages within it
+// that no project package imports.
+func explainImport(p *download.Project, out io.Writer, pkg string) error {
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return err
+	}
+	var within []string
+	for _, vendoredPkg := range vendored {
+		if download.InPackage(vendoredPkg, pkg) {
+			within = append(within, vendoredPkg)
+		}
+	}
+	if len(within) == 0 {
+		return fmt.Errorf("package %s isn't vendored", pkg)
+	}
+
+	chains, err := p.ImportChains(pkg)
+	if err != nil {
+		return err
+	}
+	for i, chain := range chains {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "# %s\n", chain[0])
+		fmt.Fprintln(out, strings.Join(chain, "\n"))
+	}
+	if len(chains) == 0 {
+		fmt.Fprintf(out, "%s isn't imported by any project package\n", pkg)
+		return nil
+	}
+
+	reachable, err := p.ReachablePackages()
+	if err != nil {
+		return err
+	}
+	var unreachable []string
+	for _, vendoredPkg := range within {
+		if !reachable[vendoredPkg] {
+			unreachable = append(unreachable, vendoredPkg)
+		}
+	}
+	if len(unreachable) > 0 {
+		fmt.Fprintln(out)
+		for _, vendoredPkg := range unreachable {
+			fmt.Fprintf(out, "%s isn't imported by any project package\n", vendoredPkg)
+		}
+	}
+	return nil
+}
+
+// reportUnreachable prints the lock entries, and the vendored packages within
+// the other entries, that no project package imports.
+func reportUnreachable(p *download.Project, logger *log.Logger, out io.Writer) error {
+	l, err := p.LoadLock()
+	if err != nil {
+		return err
+	}
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return err
+	}
+	reachable, err := p.ReachablePackages()
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for _, pkg := range l.Import {
+		var within, unreachable []string
+		for _, vendoredPkg := range vendored {
+			if !download.InPackage(vendoredPkg, pkg.Package) {
+				continue
+			}
+			within = append(within, vendoredPkg)
+			if !reachable[vendoredPkg] {
+				unreachable = append(unreachable, vendoredPkg)
+			}
+		}
+		if len(unreachable) == len(within) {
+			fmt.Fprintf(out, "lock entry %s isn't imported by any project package\n", pkg.Package)
+			n++
+			continue
+		}
+		for _, vendoredPkg := range unreachable {
+			fmt.Fprintf(out, "package %s isn't imported by any project package\n", vendoredPkg)
+			n++
+		}
+	}
+	if n == 0 {
+		logger.Printf("every vendored package is imported by the project")
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package download
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/vcs"
+	"github.com/ericchiang/godl/internal/osv"
+)
+
+// Vulnerability is an advisory affecting a locked package.
+type Vulnerability struct {
+	Package string   `json:"package"`
+	Version string   `json:"version"`
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	// Fixed lists the versions or commits fixing the vulnerability, if any.
+	Fixed []string `json:"fixed,omitempty"`
+	// Imports are the affected packages and symbols, if the advisory lists
+	// them.
+	Imports []osv.Import `json:"imports,omitempty"`
+}
+
+// Audit matches advisories against a locked package. Advisories name Go
+// modules. Only advisories for the package's own module are compared against
+// its version, since modules nested within it, such as major version suffixes
+// or modules in subdirectories, aren't versioned by its tags. Advisories for
+// nested modules match if the package's subpackages include the module, or
+// one of the affected imports when the advisory lists them.
+//
+// Packages locked to a tag are compared against the advisory's versions and
+// semantic version ranges. Git ranges, and the version ranges of packages
+// locked to a branch or revision, are evaluated against the history of the
+// package's remote in the cache, which is never fetched. Advisories that can't
+// be evaluated are skipped and reported in the returned error.
+func (p *Project) Audit(pkg LockPackage, advisories []osv.Entry) ([]Vulnerability, error) {
+	var (
+		vulns   []Vulnerability
+		skipped []string
+		hist    = &history{p: p, pkg: pkg}
+	)
+	for _, e := range advisories {
+		for _, a := range e.Affected {
+			var (
+				ok  bool
+				err error
+			)
+			switch name := a.Package.Name; {
+			case name == pkg.Package:
+				ok, err = affects(pkg, a, hist)
+			case InPackage(name, pkg.Package):
+				ok = usesNestedModule(pkg, a)
+			default:
+				continue
+			}
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s: %v", e.ID, err))
+				continue
+			}
+			if !ok {
+				continue
+			}
+			vulns = append(vulns, Vulnerability{
+				Package: pkg.Package,
+				Version: pkg.Version,
+				ID:      e.ID,
+				Aliases: e.Aliases,
+				Summary: e.Summary,
+				Fixed:   fixedVersions(a),
+				Imports: a.EcosystemSpecific.Imports,
+			})
+			break
+		}
+	}
+	if len(skipped) > 0 {
+		return vulns, fmt.Errorf("skipped advisories %s", strings.Join(skipped, "; "))
+	}
+	return vulns, nil
+}
+
+// usesNestedModule reports if the subpackages of a locked package include the
+// module of an advisory, or one of its affected imports if it lists them.
+func usesNestedModule(pkg LockPackage, a osv.Affected) bool {
+	for _, subPkg := range pkg.Subpackages {
+		importPath := path.Join(pkg.Package, subPkg)
+		if len(a.EcosystemSpecific.Imports) == 0 && InPackage(importPath, a.Package.Name) {
+			return true
+		}
+		for _, imp := range a.EcosystemSpecific.Imports {
+			if imp.Path == importPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lockedTag returns the semantic version a package is locked to, if any. Only
+// canonical tags count, so a short revision made of digits, such as "1234567",
+// is audited using its history instead.
+func lockedTag(pkg LockPackage) (semver, bool) {
+	if pkg.Revision == "" {
+		// The version is a revision.
+		return semver{}, false
+	}
+	return canonicalSemver(pkg.Version)
+}
+
+// affects reports if an advisory applies to the version of a locked package.
+func affects(pkg LockPackage, a osv.Affected, hist *history) (bool, error) {
+	for _, version := range a.Versions {
+		if version == pkg.Version || version == pkg.Rev() || "v"+version == pkg.Version {
+			return true, nil
+		}
+	}
+
+	v, tagged := lockedTag(pkg)
+	for _, r := range a.Ranges {
+		var (
+			ok  bool
+			err error
+		)
+		switch {
+		case r.Type == osv.RangeGit:
+			ok, err = hist.affected(r.Events, false)
+		case r.Type != osv.RangeSemver && r.Type != osv.RangeEcosystem:
+			err = fmt.Errorf("unsupported range type %q", r.Type)
+		case tagged:
+			ok = semverAffected(v, r.Events)
+		default:
+			ok, err = hist.affected(r.Events, true)
+		}
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// semverAffected evaluates the events of a version range against a version.
+func semverAffected(v semver, events []osv.Event) bool {
+	type point struct {
+		v     semver
+		event osv.Event
+	}
+	var points []point
+	for _, e := range events {
+		s := e.Introduced + e.Fixed + e.LastAffected + e.Limit
+		if e.Introduced == "0" {
+			points = append(points, point{semver{}, e})
+			continue
+		}
+		if pv, ok := parseSemver(s); ok {
+			points = append(points, point{pv, e})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].v.less(points[j].v) })
+
+	affected := false
+	for _, pt := range points {
+		switch {
+		case v.less(pt.v):
+			return affected
+		case pt.event.Introduced != "":
+			affected = true
+		case pt.event.Fixed != "", pt.event.Limit != "":
+			affected = false
+		case pt.event.LastAffected != "":
+			affected = !pt.v.less(v)
+		}
+	}
+	return affected
+}
+
+// fixedVersions returns the fixed events of an advisory's ranges.
+func fixedVersions(a osv.Affected) []string {
+	var fixed []string
+	for _, r := range a.Ranges {
+		for _, e := range r.Events {
+			if e.Fixed == "" {
+				continue
+			}
+			f := e.Fixed
+			if r.Type == osv.RangeSemver && !strings.HasPrefix(f, "v") {
+				f = "v" + f
+			}
+			if !ContainsString(fixed, f) {
+				fixed = append(fixed, f)
+			}
+		}
+	}
+	return fixed
+}
+
+// history answers ancestry questions about a locked revision using the cached
+// clone of its remote.
+type history struct {
+	p   *Project
+	pkg LockPackage
+}
+
+// affected evaluates the events of a range against the locked revision. If
+// tags is set, events are versions which are resolved to tags, otherwise
+// they're commits. The revision is affected if it's reached from an introduced
+// event without passing a fixed, limit or last affected event reached from the
+// same introduced event. Events that can't be found in the cached history are
+// treated as not reached.
+func (h *history) affected(events []osv.Event, tags bool) (bool, error) {
+	remote := RemoteOf(ManifestPackage{Package: h.pkg.Package, Remote: h.pkg.Remote})
+	var affected bool
+	err := h.p.Cache.Peek(remote, func(cachePath string) error {
+		repo, err := vcs.NewRepo(remote, cachePath)
+		if err != nil {
+			return fmt.Errorf("setting up remote: %v", err)
+		}
+		if !repo.CheckLocal() {
+			return fmt.Errorf("remote %s isn't cached", remote)
+		}
+		if repo.Vcs() != vcs.Git {
+			return fmt.Errorf("only git remotes can be audited by commit")
+		}
+		rev, ok := resolveCommit(repo, h.pkg.Rev())
+		if !ok {
+			return fmt.Errorf("revision %s isn't cached", h.pkg.Rev())
+		}
+
+		resolve := func(ref string) (string, bool) {
+			if !tags {
+				return resolveCommit(repo, ref)
+			}
+			if commit, ok := resolveCommit(repo, "refs/tags/v"+strings.TrimPrefix(ref, "v")); ok {
+				return commit, true
+			}
+			return resolveCommit(repo, "refs/tags/"+ref)
+		}
+		// reaches reports if commit is an ancestor of descendant, or the same
+		// commit.
+		reaches := func(commit, descendant string) (bool, error) {
+			if commit == descendant {
+				return true, nil
+			}
+			return isAncestor(repo, commit, descendant)
+		}
+
+		// An empty commit stands for an introduced event of "0".
+		var (
+			introduced []string
+			ends       []rangeEnd
+		)
+		for _, e := range events {
+			if e.Introduced == "0" {
+				introduced = append(introduced, "")
+				continue
+			}
+			if e.Introduced != "" {
+				if commit, ok := resolve(e.Introduced); ok {
+					introduced = append(introduced, commit)
+				}
+				continue
+			}
+			if commit, ok := resolve(e.Fixed + e.Limit + e.LastAffected); ok {
+				ends = append(ends, rangeEnd{commit: commit, inclusive: e.LastAffected == ""})
+			}
+		}
+
+		for _, start := range introduced {
+			if start != "" {
+				ok, err := reaches(start, rev)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			closed, err := rangeClosed(start, rev, ends, reaches)
+			if err != nil {
+				return err
+			}
+			if !closed {
+				affected = true
+				return nil
+			}
+		}
+		return nil
+	})
+	return affected, err
+}
+
+// rangeEnd is the commit of a fixed, limit or last affected event.
+type rangeEnd struct {
+	commit string
+	// inclusive is set if the commit itself is no longer affected. It's false
+	// for last affected events.
+	inclusive bool
+}
+
+// rangeClosed reports if one of the end events lies between an introduced
+// commit and the locked revision.
+func rangeClosed(start, rev string, ends []rangeEnd, reaches func(commit, descendant string) (bool, error)) (bool, error) {
+	for _, end := range ends {
+		if !end.inclusive && end.commit == rev {
+			continue
+		}
+		ok, err := reaches(end.commit, rev)
+		if err != nil {
+			return false, err
+		}
+		if ok && start != "" {
+			ok, err = reaches(start, end.commit)
+			if err != nil {
+				return false, err
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveCommit returns the full commit of a ref, if it's known to a repo.
+func resolveCommit(repo vcs.Repo, ref string) (string, bool) {
+	out, err := repo.RunFromDir("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// isAncestor reports if a commit is an ancestor of rev.
+func isAncestor(repo vcs.Repo, commit, rev string) (bool, error) {
+	out, err := repo.RunFromDir("git", "merge-base", "--is-ancestor", commit, rev)
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git merge-base: %v: %s", err, out)
+}
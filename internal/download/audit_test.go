@@ -0,0 +1,252 @@
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericchiang/godl/internal/osv"
+)
+
+func TestAudit(t *testing.T) {
+	advisories := []osv.Entry{
+		{
+			ID: "GO-0001",
+			Affected: []osv.Affected{{
+				Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar"},
+				Ranges: []osv.Range{{
+					Type: osv.RangeSemver,
+					Events: []osv.Event{
+						{Introduced: "0"}, {Fixed: "1.2.0"},
+						{Introduced: "2.0.0"}, {Fixed: "2.0.3"},
+					},
+				}},
+			}},
+		},
+		{
+			ID: "GO-0002",
+			Affected: []osv.Affected{{
+				Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar/v3"},
+				Ranges: []osv.Range{{
+					Type:   osv.RangeSemver,
+					Events: []osv.Event{{Introduced: "0"}, {LastAffected: "3.2.0"}},
+				}},
+			}},
+		},
+		{
+			ID: "GO-0003",
+			Affected: []osv.Affected{{
+				Package:  osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar"},
+				Versions: []string{"v1.5.0"},
+			}},
+		},
+		{
+			ID: "GO-0004",
+			Affected: []osv.Affected{{
+				Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/barbaz"},
+				Ranges: []osv.Range{{
+					Type:   osv.RangeSemver,
+					Events: []osv.Event{{Introduced: "0"}},
+				}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"v1.1.9", []string{"GO-0001"}},
+		{"v1.2.0", nil},
+		{"v1.5.0", []string{"GO-0003"}},
+		{"v2.0.0", []string{"GO-0001"}},
+		{"v2.0.3", nil},
+		{"v3.0.0", nil},
+		{"v3.1.0", nil},
+	}
+	p := &Project{}
+	for _, test := range tests {
+		pkg := LockPackage{Package: "github.com/foo/bar", Version: test.version, Revision: "abcdef"}
+		vulns, err := p.Audit(pkg, advisories)
+		if err != nil {
+			t.Errorf("version %s: %v", test.version, err)
+			continue
+		}
+		var got []string
+		for _, v := range vulns {
+			got = append(got, v.ID)
+		}
+		if !stringSetEq(got, test.want) {
+			t.Errorf("version %s: want=%q, got=%q", test.version, test.want, got)
+		}
+	}
+
+	// Advisories for nested modules match if a subpackage is within them, or is
+	// one of their affected imports.
+	nested := []osv.Entry{
+		advisories[1],
+		{
+			ID: "GO-0005",
+			Affected: []osv.Affected{{
+				Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar/sub"},
+				Ranges: []osv.Range{{
+					Type:   osv.RangeSemver,
+					Events: []osv.Event{{Introduced: "0"}},
+				}},
+				EcosystemSpecific: osv.EcosystemSpecific{
+					Imports: []osv.Import{{Path: "github.com/foo/bar/sub/x"}},
+				},
+			}},
+		},
+	}
+	nestedTests := []struct {
+		subPkgs []string
+		want    []string
+	}{
+		{nil, nil},
+		{[]string{"v3/baz"}, []string{"GO-0002"}},
+		{[]string{"sub"}, nil},
+		{[]string{"sub/x"}, []string{"GO-0005"}},
+	}
+	for _, test := range nestedTests {
+		pkg := LockPackage{Package: "github.com/foo/bar", Version: "v1.2.0", Revision: "abcdef", Subpackages: test.subPkgs}
+		vulns, err := p.Audit(pkg, nested)
+		if err != nil {
+			t.Errorf("subpackages %q: %v", test.subPkgs, err)
+			continue
+		}
+		var got []string
+		for _, v := range vulns {
+			got = append(got, v.ID)
+		}
+		if !stringSetEq(got, test.want) {
+			t.Errorf("subpackages %q: want=%q, got=%q", test.subPkgs, test.want, got)
+		}
+	}
+}
+
+// repoCache is a cache holding a single repo.
+type repoCache struct{ dir string }
+
+func (c repoCache) Dir(remote string, f func(dir string) error) error { return f(c.dir) }
+
+func (c repoCache) Peek(remote string, f func(dir string) error) error { return f(c.dir) }
+
+func (c repoCache) Clear() error { return nil }
+
+func (c repoCache) Entries() ([]CacheEntry, error) { return nil, nil }
+
+func (c repoCache) Prune(unusedSince time.Time) ([]CacheEntry, error) { return nil, nil }
+
+// git runs a git command in dir and returns its trimmed output.
+func git(t *testing.T, dir string, args ...string) string {
+	args = append([]string{"-c", "user.name=godl", "-c", "user.email=godl@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestAuditHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir, err := ioutil.TempDir("", "godl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	origin := filepath.Join(dir, "origin")
+	if err := os.Mkdir(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, origin, "init", "-q")
+	// Linear history where commits[i] is tagged v1.i.0.
+	var commits []string
+	for i := 0; i < 6; i++ {
+		git(t, origin, "commit", "-q", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+		git(t, origin, "tag", fmt.Sprintf("v1.%d.0", i))
+		commits = append(commits, git(t, origin, "rev-parse", "HEAD"))
+	}
+	remote := "file://" + origin
+	clone := filepath.Join(dir, "clone")
+	git(t, dir, "clone", "-q", remote, clone)
+
+	gitRange := func(events ...osv.Event) osv.Entry {
+		return osv.Entry{ID: "GIT", Affected: []osv.Affected{{
+			Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar"},
+			Ranges:  []osv.Range{{Type: osv.RangeGit, Events: events}},
+		}}}
+	}
+	semverRange := func(events ...osv.Event) osv.Entry {
+		return osv.Entry{ID: "SEMVER", Affected: []osv.Affected{{
+			Package: osv.Package{Ecosystem: "Go", Name: "github.com/foo/bar"},
+			Ranges:  []osv.Range{{Type: osv.RangeSemver, Events: events}},
+		}}}
+	}
+	reintroduced := gitRange(
+		osv.Event{Introduced: commits[1]}, osv.Event{Fixed: commits[2]},
+		osv.Event{Introduced: commits[3]}, osv.Event{Fixed: commits[5]},
+	)
+	lastAffected := gitRange(osv.Event{Introduced: "0"}, osv.Event{LastAffected: commits[2]})
+	versions := semverRange(
+		osv.Event{Introduced: "0"}, osv.Event{Fixed: "1.1.0"},
+		osv.Event{Introduced: "1.3.0"}, osv.Event{Fixed: "1.4.0"},
+	)
+
+	tests := []struct {
+		advisory osv.Entry
+		rev      int
+		want     bool
+	}{
+		{reintroduced, 0, false},
+		{reintroduced, 1, true},
+		{reintroduced, 2, false},
+		{reintroduced, 3, true},
+		{reintroduced, 4, true},
+		{reintroduced, 5, false},
+		{lastAffected, 2, true},
+		{lastAffected, 3, false},
+		{versions, 0, true},
+		{versions, 1, false},
+		{versions, 3, true},
+		{versions, 4, false},
+	}
+	p := &Project{Cache: repoCache{clone}}
+	for _, test := range tests {
+		pkg := LockPackage{Package: "github.com/foo/bar", Version: commits[test.rev], Remote: remote}
+		vulns, err := p.Audit(pkg, []osv.Entry{test.advisory})
+		if err != nil {
+			t.Errorf("%s range at commit %d: %v", test.advisory.ID, test.rev, err)
+			continue
+		}
+		if got := len(vulns) > 0; got != test.want {
+			t.Errorf("%s range at commit %d: want affected=%t, got=%t", test.advisory.ID, test.rev, test.want, got)
+		}
+	}
+
+	// A short revision made of digits isn't a tag, the history is used.
+	for _, test := range tests {
+		if test.advisory.ID != "SEMVER" {
+			continue
+		}
+		pkg := LockPackage{Package: "github.com/foo/bar", Version: "1234567", Revision: commits[test.rev], Remote: remote}
+		vulns, err := p.Audit(pkg, []osv.Entry{test.advisory})
+		if err != nil {
+			t.Errorf("short revision at commit %d: %v", test.rev, err)
+			continue
+		}
+		if got := len(vulns) > 0; got != test.want {
+			t.Errorf("short revision at commit %d: want affected=%t, got=%t", test.rev, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go4.org/lock"
+)
+
+// Cache provides a space for downloading packages.
+type Cache interface {
+	// Dir maps a remote repo to a directory.
+	Dir(remote string, f func(dir string) error) error
+	// Peek maps a remote repo to a directory like Dir, but doesn't record the
+	// repo as used. It's meant for commands that only read the cache, which
+	// must not modify the directory.
+	Peek(remote string, f func(dir string) error) error
+	// Clear removes all cached packages from disk.
+	Clear() error
+	// Entries lists the repos held by the cache, ordered by remote.
+	Entries() ([]CacheEntry, error)
+	// Prune removes repos that haven't been used since the provided time and
+	// returns them. Repos in use by another process are skipped.
+	Prune(unusedSince time.Time) ([]CacheEntry, error)
+}
+
+// CacheEntry describes a single repo held by a cache.
+type CacheEntry struct {
+	// Remote is the repo's remote URL. It's empty for entries created before
+	// the cache recorded metadata.
+	Remote string
+	// Dir is the directory holding the repo.
+	Dir string
+	// Size is the total size of the repo's files in bytes.
+	Size int64
+	// LastUsed is the last time the repo was used to download a package.
+	LastUsed time.Time
+}
+
+// NewCache returns a repo for a cache.
+func NewCache(dir string) Cache { return cacheDir{dir} }
+
+// NoCache is a cache implementation that doesn't cache anything.
+var NoCache Cache = tempDir{}
+
+type tempDir struct{}
+
+func (t tempDir) Dir(remote string, f func(dir string) error) error {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	return f(dir)
+}
+
+func (t tempDir) Peek(remote string, f func(dir string) error) error { return t.Dir(remote, f) }
+
+func (t tempDir) Clear() error { return nil }
+
+func (t tempDir) Entries() ([]CacheEntry, error) { return nil, nil }
+
+func (t tempDir) Prune(unusedSince time.Time) ([]CacheEntry, error) { return nil, nil }
+
+// cacheDir is a cache implementation that returns returns a new
+type cacheDir struct {
+	dir string
+}
+
+// cacheMetadata is written next to each cached repo so the hashed directory
+// names can be mapped back to their remote.
+type cacheMetadata struct {
+	Remote   string    `json:"remote"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+const (
+	lockExt     = ".lock"
+	metadataExt = ".json"
+)
+
+func (c cacheDir) Clear() error {
+	return os.RemoveAll(c.dir)
+}
+
+func (c cacheDir) srcDir() string {
+	return filepath.Join(c.dir, "src")
+}
+
+func (c cacheDir) repoDir(remote string) string {
+	h := sha256.New()
+	io.WriteString(h, remote)
+	return filepath.Join(c.srcDir(), hex.EncodeToString(h.Sum(nil)))
+}
+
+func (c cacheDir) Dir(remote string, f func(dir string) error) error {
+	dir := c.repoDir(remote)
+
+	lockFile := dir + lockExt
+	if err := os.MkdirAll(filepath.Dir(lockFile), 0755); err != nil {
+		return err
+	}
+
+	closer, err := lock.Lock(lockFile)
+	if err != nil {
+		return fmt.Errorf("could not create lock file for remote %s, is another process downloading that package? (%v)", remote, err)
+	}
+	defer closer.Close()
+
+	data, err := json.Marshal(cacheMetadata{Remote: remote, LastUsed: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(dir+metadataExt, data, 0644); err != nil {
+		return fmt.Errorf("writing cache metadata: %v", err)
+	}
+
+	return f(dir)
+}
+
+func (c cacheDir) Peek(remote string, f func(dir string) error) error {
+	dir := c.repoDir(remote)
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			// Nothing is cached, let f find an empty directory.
+			return f(dir)
+		}
+		return err
+	}
+
+	closer, err := lock.Lock(dir + lockExt)
+	if err != nil {
+		return fmt.Errorf("could not create lock file for remote %s, is another process downloading that package? (%v)", remote, err)
+	}
+	defer closer.Close()
+	return f(dir)
+}
+
+func (c cacheDir) Entries() ([]CacheEntry, error) {
+	infos, err := ioutil.ReadDir(c.srcDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		e, err := c.entry(info)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Remote != entries[j].Remote {
+			return entries[i].Remote < entries[j].Remote
+		}
+		return entries[i].Dir < entries[j].Dir
+	})
+	return entries, nil
+}
+
+func (c cacheDir) entry(info os.FileInfo) (CacheEntry, error) {
+	dir := filepath.Join(c.srcDir(), info.Name())
+	e := CacheEntry{Dir: dir, LastUsed: info.ModTime()}
+
+	data, err := ioutil.ReadFile(dir + metadataExt)
+	if err == nil {
+		var m cacheMetadata
+		if err := json.Unmarshal(data, &m); err != nil {
+			return e, fmt.Errorf("parsing cache metadata %s: %v", dir+metadataExt, err)
+		}
+		e.Remote = m.Remote
+		e.LastUsed = m.LastUsed
+	} else if !os.IsNotExist(err) {
+		return e, err
+	}
+
+	e.Size, err = dirSize(dir)
+	return e, err
+}
+
+func (c cacheDir) Prune(unusedSince time.Time) ([]CacheEntry, error) {
+	entries, err := c.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []CacheEntry
+	for _, e := range entries {
+		if !e.LastUsed.Before(unusedSince) {
+			continue
+		}
+		ok, err := removeCacheEntry(e.Dir)
+		if err != nil {
+			return pruned, err
+		}
+		if ok {
+			pruned = append(pruned, e)
+		}
+	}
+	return pruned, nil
+}
+
+// removeCacheEntry deletes a cached repo and its metadata. If the repo is in
+// use by another process, it's left alone and false is returned.
+func removeCacheEntry(dir string) (bool, error) {
+	closer, err := lock.Lock(dir + lockExt)
+	if err != nil {
+		return false, nil
+	}
+	defer os.Remove(dir + lockExt)
+	defer closer.Close()
+
+	if err := os.RemoveAll(dir); err != nil {
+		return false, err
+	}
+	if err := os.Remove(dir + metadataExt); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// dirSize returns the total size of the regular files under a directory.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,124 @@
+package download
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCachePrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewCache(dir)
+	remotes := []string{"https://github.com/a/b", "https://github.com/c/d"}
+	for _, remote := range remotes {
+		err := c.Dir(remote, func(dir string) error {
+			return testfile{"file.go", "package b"}.write(dir)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(remotes) {
+		t.Fatalf("expected %d entries got %d", len(remotes), len(entries))
+	}
+	for i, e := range entries {
+		if e.Remote != remotes[i] {
+			t.Errorf("expected entry %d to have remote %s got %s", i, remotes[i], e.Remote)
+		}
+		if e.Size != int64(len("package b")) {
+			t.Errorf("expected entry %s to have size %d got %d", e.Remote, len("package b"), e.Size)
+		}
+	}
+
+	pruned, err := c.Prune(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 0 {
+		t.Errorf("expected recently used entries to be kept, pruned %d", len(pruned))
+	}
+
+	pruned, err = c.Prune(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != len(remotes) {
+		t.Errorf("expected %d entries to be pruned got %d", len(remotes), len(pruned))
+	}
+	files, err := ioutil.ReadDir(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("expected cache to be empty, found %d files", len(files))
+	}
+}
+
+func TestCachePeek(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewCache(dir)
+	remote := "https://github.com/a/b"
+	var cached string
+	err = c.Peek(remote, func(dir string) error {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected uncached repo to not exist, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src")); !os.IsNotExist(err) {
+		t.Errorf("expected peeking to not create the cache, got %v", err)
+	}
+
+	err = c.Dir(remote, func(dir string) error {
+		cached = dir
+		return testfile{"file.go", "package b"}.write(dir)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry got %d", len(entries))
+	}
+	lastUsed := entries[0].LastUsed
+
+	time.Sleep(10 * time.Millisecond)
+	err = c.Peek(remote, func(dir string) error {
+		if dir != cached {
+			t.Errorf("expected peek to return %s got %s", cached, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = c.Entries(); err != nil {
+		t.Fatal(err)
+	}
+	if !entries[0].LastUsed.Equal(lastUsed) {
+		t.Errorf("expected peeking to keep last used time %s got %s", lastUsed, entries[0].LastUsed)
+	}
+}
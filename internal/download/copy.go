@@ -16,8 +16,26 @@ func isGoFile(name string) bool {
 	return filepath.Ext(name) == ".go" && !strings.HasSuffix(name, "_test.go")
 }
 
+// isLicense reports if a file holds licensing terms, such as LICENSE, COPYING
+// or NOTICE files, optionally with a .txt, .md or .rst extension. Variants such
+// as LICENSE-MIT and COPYING.LESSER are included.
 func isLicense(name string) bool {
-	return name == "LICENSE" || name == "LICENSE.txt"
+	name = strings.ToUpper(name)
+	ext := filepath.Ext(name)
+	if strings.HasPrefix(name, "COPYING.") && ext != ".GO" && !strings.Contains(strings.TrimPrefix(name, "COPYING."), ".") {
+		return true
+	}
+	switch ext {
+	case "", ".TXT", ".MD", ".RST":
+	default:
+		return false
+	}
+	switch stem := strings.TrimSuffix(name, ext); stem {
+	case "LICENSE", "LICENCE", "COPYING", "NOTICE", "PATENTS":
+		return true
+	default:
+		return strings.HasPrefix(stem, "LICENSE-") || strings.HasPrefix(stem, "LICENCE-")
+	}
 }
 
 func ignore(info os.FileInfo) bool {
@@ -125,8 +143,9 @@ func walkImports(pkg string, pkgPath func(pkgName string) string, visit func(pkg
 }
 
 // copySubpackages recursively follows subpackage imports as long as
-// the import is within the package.
-func copySubpackages(dest, pkgRoot string, p ManifestPackage) error {
+// the import is within the package. It returns the hash of the files copied
+// to dest.
+func copySubpackages(dest, pkgRoot string, p ManifestPackage) (string, error) {
 	visitedPkgs := make(map[string]bool)
 
 	absPath := func(root, pkgName string) string {
@@ -169,10 +188,10 @@ func copySubpackages(dest, pkgRoot string, p ManifestPackage) error {
 
 	for _, pkg := range toVisit {
 		if err := walkImports(pkg, pkgPath, visit); err != nil {
-			return err
+			return "", err
 		}
 	}
-	return nil
+	return hashTree(dest)
 }
 
 func isMain(pkgPath string) (bool, error) {
@@ -41,6 +41,18 @@ func TestIgnore(t *testing.T) {
 		{name: "README.md", want: true},
 		{name: "symlink.go", mode: os.ModeSymlink, want: true},
 		{name: "LICENSE"},
+		{name: "LICENSE.md"},
+		{name: "LICENCE"},
+		{name: "COPYING"},
+		{name: "NOTICE.txt"},
+		{name: "PATENTS"},
+		{name: "LICENSE.go.tmpl", want: true},
+		{name: "LICENSE.rst"},
+		{name: "LICENSE-MIT"},
+		{name: "LICENSE-APACHE.txt"},
+		{name: "COPYING.LESSER"},
+		{name: "COPYING.go.tmpl", want: true},
+		{name: "LICENSE-MIT.html", want: true},
 	}
 	for _, test := range tests {
 		info := &fakeFile{test.name, test.mode}
@@ -51,6 +63,30 @@ func TestIgnore(t *testing.T) {
 	}
 }
 
+// TestIgnoreVendorDir checks that godl keeps every file of its own vendor
+// directory, so the vendored packages match what godl vendor produces.
+func TestIgnoreVendorDir(t *testing.T) {
+	vendorDir := filepath.Join("..", "..", "vendor")
+	if _, err := os.Stat(vendorDir); err != nil {
+		t.Skipf("no vendor directory: %v", err)
+	}
+	err := filepath.Walk(vendorDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != vendorDir && ignore(info) {
+			t.Errorf("vendored file %s would be removed by godl vendor", path)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestCopyFile(t *testing.T) {
 	p := "foo/bar.go"
 	test := copyTest{
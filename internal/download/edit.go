@@ -0,0 +1,174 @@
+package download
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// manifestEntry is the text of a package in the manifest's import list.
+type manifestEntry struct {
+	pkg ManifestPackage
+	// leading holds the comments and blank lines before the entry.
+	leading []string
+	lines   []string
+}
+
+// editManifest renders an updated manifest while keeping the comments and
+// formatting of the original file. Entries of the import list that didn't
+// change are kept as is, along with the comments before each entry. Changed
+// and new entries are rendered in place. If the original can't be edited this
+// way, such as when a field other than the import list changed, the manifest
+// is rendered from scratch.
+func editManifest(original []byte, m *Manifest) ([]byte, error) {
+	if data, ok := editImports(original, m); ok {
+		return data, nil
+	}
+	return yaml.Marshal(m)
+}
+
+func editImports(original []byte, m *Manifest) ([]byte, bool) {
+	var old Manifest
+	if err := yaml.Unmarshal(original, &old); err != nil {
+		return nil, false
+	}
+	oldRest, newRest := old, *m
+	oldRest.Import, newRest.Import = nil, nil
+	if len(m.Import) == 0 || !reflect.DeepEqual(oldRest, newRest) {
+		return nil, false
+	}
+
+	lines := strings.SplitAfter(string(original), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines[n-1] += "\n"
+	}
+	start := -1
+	for i, line := range lines {
+		if strings.TrimRight(line, " \r\n") == "import:" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+
+	// Split the import list into entries. Comments and blank lines belong to
+	// the entry that follows them, or to the rest of the file if no entry does.
+	var (
+		entries []manifestEntry
+		pending []string
+		indent  = -1
+		end     = -1
+	)
+	for i := start + 1; i < len(lines) && end < 0; i++ {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+		if t := strings.TrimSpace(trimmed); t == "" || strings.HasPrefix(t, "#") {
+			pending = append(pending, line)
+			continue
+		}
+		lineIndent := len(line) - len(trimmed)
+		if indent < 0 && strings.HasPrefix(trimmed, "- ") {
+			indent = lineIndent
+		}
+		switch {
+		case lineIndent == indent && strings.HasPrefix(trimmed, "- "):
+			entries = append(entries, manifestEntry{leading: pending, lines: []string{line}})
+		case lineIndent > indent && len(entries) > 0:
+			last := &entries[len(entries)-1]
+			last.lines = append(last.lines, pending...)
+			last.lines = append(last.lines, line)
+		default:
+			end = i
+			continue
+		}
+		pending = nil
+	}
+	if end < 0 {
+		end = len(lines)
+	}
+	end -= len(pending)
+	if indent < 0 {
+		return nil, false
+	}
+	if len(entries) != len(old.Import) {
+		return nil, false
+	}
+	for i := range entries {
+		var pkgs []ManifestPackage
+		if err := yaml.Unmarshal([]byte(dedent(entries[i].lines, indent)), &pkgs); err != nil || len(pkgs) != 1 {
+			return nil, false
+		}
+		entries[i].pkg = pkgs[0]
+	}
+
+	var b bytes.Buffer
+	for _, line := range lines[:start+1] {
+		b.WriteString(line)
+	}
+	for _, pkg := range m.Import {
+		var e *manifestEntry
+		for i := range entries {
+			if entries[i].pkg.Package == pkg.Package {
+				e = &entries[i]
+				break
+			}
+		}
+		if e != nil {
+			for _, line := range e.leading {
+				b.WriteString(line)
+			}
+			if reflect.DeepEqual(e.pkg, pkg) {
+				for _, line := range e.lines {
+					b.WriteString(line)
+				}
+				continue
+			}
+		}
+		data, err := yaml.Marshal([]ManifestPackage{pkg})
+		if err != nil {
+			return nil, false
+		}
+		for _, line := range strings.SplitAfter(string(data), "\n") {
+			if line != "" {
+				b.WriteString(strings.Repeat(" ", indent) + line)
+			}
+		}
+	}
+	for _, line := range lines[end:] {
+		b.WriteString(line)
+	}
+
+	// Only keep the edit if it describes the same manifest.
+	var got Manifest
+	if err := yaml.Unmarshal(b.Bytes(), &got); err != nil {
+		return nil, false
+	}
+	gotData, err := yaml.Marshal(&got)
+	if err != nil {
+		return nil, false
+	}
+	wantData, err := yaml.Marshal(m)
+	if err != nil || !bytes.Equal(gotData, wantData) {
+		return nil, false
+	}
+	return b.Bytes(), true
+}
+
+// dedent removes the indentation of a list from lines.
+func dedent(lines []string, indent int) string {
+	var b bytes.Buffer
+	for _, line := range lines {
+		if len(line)-len(strings.TrimLeft(line, " ")) >= indent {
+			line = line[indent:]
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package download
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestEditManifest(t *testing.T) {
+	original := `# Dependencies of godl.
+import:
+- package: go4.org
+  version: 16ace784e4b16df1d51c3435223b1d602cd43bfa
+  # Only the lock package is used.
+  subpackages:
+  - lock
+
+- package: github.com/Masterminds/vcs
+  version: v1.11.1
+
+# Cobra and related tools
+- package: github.com/spf13/cobra
+  version: 1362f95a8d6fe330d00a64380d6e0b65f4992c72
+- package: github.com/spf13/pflag
+  version: e57e3eeb33f795204c1ca35f56c44f83227c6e66
+
+# Trailing comment.
+`
+	var m Manifest
+	if err := yaml.Unmarshal([]byte(original), &m); err != nil {
+		t.Fatal(err)
+	}
+	// Update cobra, remove vcs and add a new package.
+	m.Import = []ManifestPackage{
+		m.Import[0],
+		{Package: "github.com/spf13/cobra", Version: "v0.0.5", Subpackages: []string{"doc"}},
+		m.Import[3],
+		{Package: "github.com/ghodss/yaml", Version: "v1.0.0"},
+	}
+
+	got, err := editManifest([]byte(original), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Dependencies of godl.
+import:
+- package: go4.org
+  version: 16ace784e4b16df1d51c3435223b1d602cd43bfa
+  # Only the lock package is used.
+  subpackages:
+  - lock
+
+# Cobra and related tools
+- package: github.com/spf13/cobra
+  subpackages:
+  - doc
+  version: v0.0.5
+- package: github.com/spf13/pflag
+  version: e57e3eeb33f795204c1ca35f56c44f83227c6e66
+- package: github.com/ghodss/yaml
+  version: v1.0.0
+
+# Trailing comment.
+`
+	if string(got) != want {
+		t.Errorf("expected manifest:\n%s\ngot:\n%s", want, got)
+	}
+
+	// Changes outside the import list render the manifest from scratch.
+	m.Licenses = &LicensePolicy{Deny: []string{"GPL-3.0-only"}}
+	got, err = editManifest([]byte(original), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotManifest Manifest
+	if err := yaml.Unmarshal(got, &gotManifest); err != nil {
+		t.Fatal(err)
+	}
+	if gotManifest.Licenses == nil || len(gotManifest.Import) != 4 {
+		t.Errorf("expected rendered manifest to match, got:\n%s", got)
+	}
+}
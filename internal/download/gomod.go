@@ -0,0 +1,243 @@
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/vcs"
+)
+
+// Module is a locked package described as a Go module requirement.
+type Module struct {
+	Path    string
+	Version string
+	// Packages are the import paths of the module's vendored packages, sorted.
+	Packages []string
+}
+
+// Modules converts each package of the lock file into a module. Versions that
+// are canonical semantic version tags are kept, other versions are converted
+// to pseudo-versions using the commit date of the revision in the cache.
+func (p *Project) Modules() ([]Module, error) {
+	l, err := p.LoadLock()
+	if err != nil {
+		return nil, err
+	}
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []Module
+	for _, pkg := range l.Import {
+		mod := Module{Path: pkg.Package, Version: tagVersion(pkg.Package, pkg.Version)}
+		if mod.Version == "" {
+			rev, date, err := p.commitInfo(pkg, true)
+			if err != nil {
+				return nil, fmt.Errorf("package %s: %v", pkg.Package, err)
+			}
+			mod.Version = pseudoVersion(pkg.Package, rev, date)
+		}
+		for _, vendoredPkg := range vendored {
+			if InPackage(vendoredPkg, pkg.Package) {
+				mod.Packages = append(mod.Packages, vendoredPkg)
+			}
+		}
+		mods = append(mods, mod)
+	}
+	return mods, nil
+}
+
+// RevisionDate returns the commit date of a locked package's revision. Only
+// the cache is consulted, an error is returned if the remote isn't cached.
+func (p *Project) RevisionDate(pkg LockPackage) (time.Time, error) {
+	_, date, err := p.commitInfo(pkg, false)
+	return date, err
+}
+
+// commitInfo returns the full revision and commit date of a locked package.
+// If fetch is set, the remote is fetched when it isn't already in the cache.
+// Otherwise the cache is only read, and isn't marked as used.
+func (p *Project) commitInfo(pkg LockPackage, fetch bool) (rev string, date time.Time, err error) {
+	remote := RemoteOf(ManifestPackage{Package: pkg.Package, Remote: pkg.Remote})
+	dir := p.Cache.Peek
+	if fetch {
+		dir = p.Cache.Dir
+	}
+	err = dir(remote, func(cachePath string) error {
+		repo, err := vcs.NewRepo(remote, cachePath)
+		if err != nil {
+			return fmt.Errorf("setting up remote: %v", err)
+		}
+		if !repo.CheckLocal() {
+			if !fetch {
+				return fmt.Errorf("remote %s isn't cached", remote)
+			}
+			if _, err := downloadRepo(repo, ""); err != nil {
+				return fmt.Errorf("download repo: %v", err)
+			}
+		}
+		ci, err := repo.CommitInfo(pkg.Rev())
+		if err != nil {
+			return fmt.Errorf("revision %s: %v", pkg.Rev(), err)
+		}
+		rev, date = ci.Commit, ci.Date
+		return nil
+	})
+	return rev, date, err
+}
+
+// pathMajor matches the major version suffix of a module path, such as "/v2"
+// or the ".v2" of gopkg.in paths.
+var pathMajor = regexp.MustCompile(`(?:^gopkg\.in/.*\.|/)v([0-9]+)$`)
+
+// majorVersion returns the major version required by a module path, or -1 if
+// the path has no major version suffix.
+func majorVersion(modPath string) int {
+	m := pathMajor.FindStringSubmatch(modPath)
+	if m == nil {
+		return -1
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// tagVersion returns the module version of a tag, or an empty string if the
+// tag isn't a canonical semantic version. Major versions above 1 without a
+// matching path suffix are marked "+incompatible".
+func tagVersion(modPath, tag string) string {
+	v, ok := canonicalSemver(tag)
+	if !ok {
+		return ""
+	}
+
+	switch major := majorVersion(modPath); {
+	case major == v.major:
+		return tag
+	case major >= 0:
+		// The tag doesn't match the major version of the path.
+		return ""
+	case v.major >= 2:
+		return tag + "+incompatible"
+	}
+	return tag
+}
+
+// pseudoVersion returns the Go module pseudo-version of a revision.
+func pseudoVersion(modPath, rev string, date time.Time) string {
+	major := majorVersion(modPath)
+	if major < 0 || (major == 1 && !strings.HasPrefix(modPath, "gopkg.in/")) {
+		major = 0
+	}
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return fmt.Sprintf("v%d.0.0-%s-%s", major, date.UTC().Format("20060102150405"), rev)
+}
+
+// GoMod holds the directives of a go.mod file used by godl.
+type GoMod struct {
+	// Module is the module path.
+	Module string
+	// Go is the version of the go directive.
+	Go      string
+	Require []ModuleVersion
+	Replace []GoModReplace
+}
+
+// ModuleVersion is a module path and version. The version of a replacement
+// is empty if it's a local directory, or if a replace directive applies to
+// every version of a module.
+type ModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// GoModReplace is a replace directive.
+type GoModReplace struct {
+	Old ModuleVersion
+	New ModuleVersion
+}
+
+// ReadGoMod reads and parses a go.mod file.
+func ReadGoMod(path string) (*GoMod, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGoMod(data)
+}
+
+// ParseGoMod parses a go.mod file. Comments are ignored, as are directives
+// other than module, go, require and replace.
+func ParseGoMod(data []byte) (*GoMod, error) {
+	var (
+		mod   GoMod
+		block string
+	)
+	for i, line := range strings.Split(string(data), "\n") {
+		if j := strings.Index(line, "//"); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(strings.Replace(line, `"`, "", -1))
+		if len(fields) == 0 {
+			continue
+		}
+
+		verb := block
+		switch {
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "":
+			verb, fields = fields[0], fields[1:]
+		}
+
+		switch verb {
+		case "module":
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("go.mod:%d: invalid module directive", i+1)
+			}
+			mod.Module = fields[0]
+		case "go":
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("go.mod:%d: invalid go directive", i+1)
+			}
+			mod.Go = fields[0]
+		case "require":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("go.mod:%d: invalid require directive", i+1)
+			}
+			mod.Require = append(mod.Require, ModuleVersion{Path: fields[0], Version: fields[1]})
+		case "replace":
+			// Formats are "old [version] => new [version]".
+			arrow := -1
+			for j, f := range fields {
+				if f == "=>" {
+					arrow = j
+				}
+			}
+			if arrow < 1 || arrow > 2 || len(fields)-arrow < 2 || len(fields)-arrow > 3 {
+				return nil, fmt.Errorf("go.mod:%d: invalid replace directive", i+1)
+			}
+			var r GoModReplace
+			r.Old.Path = fields[0]
+			if arrow == 2 {
+				r.Old.Version = fields[1]
+			}
+			r.New.Path = fields[arrow+1]
+			if len(fields)-arrow == 3 {
+				r.New.Version = fields[arrow+2]
+			}
+			mod.Replace = append(mod.Replace, r)
+		}
+	}
+	return &mod, nil
+}
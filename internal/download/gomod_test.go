@@ -0,0 +1,89 @@
+package download
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTagVersion(t *testing.T) {
+	tests := []struct {
+		path, tag string
+		want      string
+	}{
+		{"github.com/a/b", "v1.2.3", "v1.2.3"},
+		{"github.com/a/b", "v1.0.0-rc.1", "v1.0.0-rc.1"},
+		{"github.com/a/b", "v1.2", ""},
+		{"github.com/a/b", "1.2.3", ""},
+		{"github.com/a/b", "master", ""},
+		{"github.com/a/b", "v2.1.0", "v2.1.0+incompatible"},
+		{"github.com/a/b/v2", "v2.1.0", "v2.1.0"},
+		{"github.com/a/b/v2", "v1.0.0", ""},
+		{"gopkg.in/yaml.v2", "v2.1.0", "v2.1.0"},
+	}
+	for _, test := range tests {
+		if got := tagVersion(test.path, test.tag); got != test.want {
+			t.Errorf("tagVersion(%q, %q), want=%q, got=%q", test.path, test.tag, test.want, got)
+		}
+	}
+}
+
+func TestPseudoVersion(t *testing.T) {
+	date := time.Date(2018, 3, 4, 5, 6, 7, 0, time.FixedZone("PST", -8*60*60))
+	rev := "feeb485667d1fdabe727840fe00adc22431bc86e"
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"golang.org/x/net", "v0.0.0-20180304130607-feeb485667d1"},
+		{"github.com/a/b/v3", "v3.0.0-20180304130607-feeb485667d1"},
+		{"gopkg.in/yaml.v2", "v2.0.0-20180304130607-feeb485667d1"},
+	}
+	for _, test := range tests {
+		if got := pseudoVersion(test.path, rev, date); got != test.want {
+			t.Errorf("pseudoVersion(%q), want=%q, got=%q", test.path, test.want, got)
+		}
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	data := `module "github.com/me/project" // the project
+
+go 1.12
+
+require (
+	github.com/foo/bar v1.2.3 // indirect
+)
+
+require github.com/foo/single v0.1.0
+
+replace github.com/foo/bar v1.2.3 => github.com/fork/bar v1.2.4
+
+replace (
+	github.com/foo/local => ../local
+)
+`
+	got, err := ParseGoMod([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GoMod{
+		Module: "github.com/me/project",
+		Go:     "1.12",
+		Require: []ModuleVersion{
+			{"github.com/foo/bar", "v1.2.3"},
+			{"github.com/foo/single", "v0.1.0"},
+		},
+		Replace: []GoModReplace{
+			{ModuleVersion{"github.com/foo/bar", "v1.2.3"}, ModuleVersion{"github.com/fork/bar", "v1.2.4"}},
+			{ModuleVersion{"github.com/foo/local", ""}, ModuleVersion{"../local", ""}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v got %#v", want, got)
+	}
+
+	if _, err := ParseGoMod([]byte("require github.com/foo/bar\n")); err == nil {
+		t.Errorf("expected error for require directive without a version")
+	}
+}
@@ -0,0 +1,139 @@
+package download
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportGraph maps import paths to the sorted import paths they import.
+type ImportGraph map[string][]string
+
+// VendorImports returns the imports between the project's vendored packages.
+// Imports of packages that aren't vendored, such as the standard library, are
+// omitted. Test files aren't considered.
+func (p *Project) VendorImports() (ImportGraph, error) {
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+	isVendored := make(map[string]bool)
+	for _, pkg := range vendored {
+		isVendored[pkg] = true
+	}
+
+	g := make(ImportGraph)
+	for _, pkg := range vendored {
+		imports, err := packageImports(pkg, p.PackageDir, func(importPath string) bool {
+			return isVendored[importPath]
+		})
+		if err != nil {
+			return nil, err
+		}
+		g[pkg] = imports
+	}
+	return g, nil
+}
+
+// packageImports returns the direct imports of a package that match the
+// filter, sorted.
+func packageImports(pkg string, pkgPath func(pkgName string) string, filter func(importPath string) bool) ([]string, error) {
+	var imports []string
+	seen := make(map[string]bool)
+	err := walkImports(pkg, pkgPath, func(importPath string) (bool, error) {
+		if importPath == pkg {
+			// Read the files of the package itself, but don't follow its imports.
+			return true, nil
+		}
+		if !seen[importPath] && filter(importPath) {
+			imports = append(imports, importPath)
+		}
+		seen[importPath] = true
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// ProjectGraph returns the imports of the project's own packages. Only imports
+// of other project packages and of vendored packages are kept, and test files
+// aren't considered. Packages are named using the project's import path, and
+// an error is returned if it can't be determined.
+func (p *Project) ProjectGraph() (ImportGraph, error) {
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.ProjectImports()
+	if err != nil {
+		return nil, err
+	}
+	for file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			delete(files, file)
+		}
+	}
+	g := make(ImportGraph)
+	if len(files) == 0 {
+		return g, nil
+	}
+
+	root, err := p.ImportPath()
+	if err != nil {
+		return nil, err
+	}
+	for file := range files {
+		g[path.Join(root, path.Dir(file))] = nil
+	}
+	for file, imports := range files {
+		pkg := path.Join(root, path.Dir(file))
+		for _, importPath := range imports {
+			_, isProject := g[importPath]
+			if (isProject || ContainsString(vendored, importPath)) && importPath != pkg && !ContainsString(g[pkg], importPath) {
+				g[pkg] = append(g[pkg], importPath)
+			}
+		}
+	}
+	for _, imports := range g {
+		sort.Strings(imports)
+	}
+	return g, nil
+}
+
+// ImportPath returns the import path of the project directory. It's the module
+// path of the project's go.mod file if it has one, otherwise the directory's
+// path within GOPATH.
+func (p *Project) ImportPath() (string, error) {
+	mod, err := ReadGoMod(filepath.Join(p.Dir, "go.mod"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err == nil && mod.Module != "" {
+		return mod.Module, nil
+	}
+
+	dir, err := filepath.Abs(p.Dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		src := filepath.Join(gopath, "src")
+		if resolved, err := filepath.EvalSymlinks(src); err == nil {
+			src = resolved
+		}
+		if rel, err := filepath.Rel(src, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel), nil
+		}
+	}
+	return "", fmt.Errorf("can't determine the import path of %s, add a go.mod file with a module directive or move the project into GOPATH", p.Dir)
+}
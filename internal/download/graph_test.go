@@ -0,0 +1,133 @@
+package download
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestVendorImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []testfile{
+		{"vendor/github.com/a/b/b.go", `package b
+
+		import (
+			"fmt"
+
+			"github.com/a/b/c"
+			"github.com/d/e"
+		)
+		`},
+		{"vendor/github.com/a/b/b2.go", `package b
+
+		import "github.com/d/e"
+		`},
+		{"vendor/github.com/a/b/b_test.go", `package b
+
+		import "github.com/x/y"
+		`},
+		{"vendor/github.com/a/b/c/c.go", "package c"},
+		{"vendor/github.com/d/e/e.go", "package e"},
+		{"vendor/github.com/x/y/y.go", "package y"},
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Project{Dir: dir}
+	got, err := p.VendorImports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ImportGraph{
+		"github.com/a/b":   {"github.com/a/b/c", "github.com/d/e"},
+		"github.com/a/b/c": nil,
+		"github.com/d/e":   nil,
+		"github.com/x/y":   nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected imports %q got %q", want, got)
+	}
+}
+
+func TestProjectGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []testfile{
+		{"go.mod", "module example.com/app\n"},
+		{"main.go", `package main
+
+		import (
+			"fmt"
+
+			"example.com/app/lib"
+			"github.com/a/b"
+		)
+		`},
+		{"lib/lib.go", `package lib
+
+		import (
+			"example.com/other"
+			"github.com/a/b/c"
+		)
+		`},
+		{"lib/lib_test.go", `package lib
+
+		import "github.com/d/e"
+		`},
+		{"vendor/github.com/a/b/b.go", "package b"},
+		{"vendor/github.com/a/b/c/c.go", "package c"},
+		{"vendor/github.com/d/e/e.go", "package e"},
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Project{Dir: dir}
+	got, err := p.ProjectGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ImportGraph{
+		"example.com/app":     {"example.com/app/lib", "github.com/a/b"},
+		"example.com/app/lib": {"github.com/a/b/c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected imports %q got %q", want, got)
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &Project{Dir: dir}
+	if _, err := p.ImportPath(); err == nil {
+		t.Errorf("expected error for project without go.mod outside GOPATH")
+	}
+
+	files := []testfile{{"go.mod", "module example.com/app // the app\n"}}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.ImportPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "example.com/app"; got != want {
+		t.Errorf("expected import path %q got %q", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+package download
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// hashPrefix identifies the algorithm used by hashTree.
+const hashPrefix = "sha256:"
+
+// HashHex returns the hex digest of a hash recorded in a lock file.
+func HashHex(hash string) string {
+	return strings.TrimPrefix(hash, hashPrefix)
+}
+
+// hashTree computes a deterministic hash of the regular files under a
+// directory. Each file contributes a line holding the SHA-256 of its contents
+// and its slash separated path relative to the directory. The lines are
+// sorted by path and hashed again, so the result doesn't depend on file
+// system ordering, timestamps or permissions.
+func hashTree(dir string) (string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	// Sort slash separated paths so the order doesn't depend on the OS.
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		fileHash, err := hashFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%x  %s\n", fileHash, file)
+	}
+	return fmt.Sprintf("%s%x", hashPrefix, h.Sum(nil)), nil
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// HashPackage computes the hash of a package in the vendor directory, in the
+// same format recorded by the lock file.
+func (p *Project) HashPackage(importPath string) (string, error) {
+	dir := p.PackageDir(importPath)
+	if _, err := os.Stat(dir); err != nil {
+		return "", err
+	}
+	return hashTree(dir)
+}
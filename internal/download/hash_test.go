@@ -0,0 +1,89 @@
+package download
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []testfile{
+		{"a/foo.go", "package foo"},
+		{"a/bar/bar.go", "package bar"},
+		{"a/LICENSE", "MIT"},
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the same tree in a different order with different permissions.
+	for i := len(files) - 1; i >= 0; i-- {
+		if err := files[i].write(filepath.Join(dir, "b")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(dir, "b", "a", "foo.go"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hashA, err := hashTree(filepath.Join(dir, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashB, err := hashTree(filepath.Join(dir, "b", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashA != hashB {
+		t.Errorf("expected identical trees to have the same hash, got %s and %s", hashA, hashB)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "b", "a", "bar", "bar.go"), []byte("package baz"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hashB, err = hashTree(filepath.Join(dir, "b", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashA == hashB {
+		t.Errorf("expected modified tree to have a different hash")
+	}
+}
+
+func TestHashTreeOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// "a/b" sorts before "a0", but "a\b" wouldn't on Windows.
+	files := []testfile{
+		{"a0", "zero"},
+		{"a/b", "b"},
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	got, err := hashTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := sha256.New()
+	for _, f := range []testfile{files[1], files[0]} {
+		fmt.Fprintf(h, "%x  %s\n", sha256.Sum256([]byte(f.contents)), f.path)
+	}
+	if want := fmt.Sprintf("%s%x", hashPrefix, h.Sum(nil)); got != want {
+		t.Errorf("expected hash %s got %s", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectImports parses every Go file in the project, including tests, and
+// returns their imports keyed by the file's slash separated path relative to
+// the project directory. The vendor directory, testdata directories and hidden
+// directories are skipped.
+func (p *Project) ProjectImports() (map[string][]string, error) {
+	files := make(map[string][]string)
+	err := filepath.Walk(p.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != p.Dir && ignore(info) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(info.Name()) != ".go" {
+			return nil
+		}
+		imports, err := listImports(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = imports
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// InPackage reports if importPath is pkg or one of pkg's subpackages.
+func InPackage(importPath, pkg string) bool {
+	return importPath == pkg || strings.HasPrefix(importPath, pkg+"/")
+}
@@ -0,0 +1,112 @@
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/license"
+)
+
+// LicenseFile is a license file kept in the vendor directory.
+type LicenseFile struct {
+	// Path is the slash separated path of the file relative to the package.
+	Path string `json:"path"`
+	// Licenses are the SPDX identifiers of the licenses found in the file. It's
+	// empty if the file, such as a NOTICE file, doesn't hold a known license.
+	Licenses []string `json:"licenses"`
+}
+
+// LicenseFiles returns the license files of a vendored package, including
+// those of its subpackages.
+func (p *Project) LicenseFiles(importPath string) ([]LicenseFile, error) {
+	dir := p.PackageDir(importPath)
+	var files []LicenseFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || !isLicense(info.Name()) {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, LicenseFile{
+			Path:     filepath.ToSlash(rel),
+			Licenses: license.Classify(string(data)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// LicenseIDs returns the distinct licenses found in license files, sorted.
+func LicenseIDs(files []LicenseFile) []string {
+	var ids []string
+	for _, f := range files {
+		for _, id := range f.Licenses {
+			if !ContainsString(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LicensePolicy restricts the licenses of a project's dependencies using SPDX
+// identifiers. Deprecated identifiers, such as "GPL-3.0", are accepted, and the
+// "-only" and "-or-later" variants of a GNU license match the same files.
+type LicensePolicy struct {
+	// Allow lists the only licenses dependencies may use. If empty, any license
+	// not denied is allowed.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists licenses dependencies must not use.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// Check returns an error if the license files of a package violate the policy.
+// Packages without license files always violate it. If an allow list is set,
+// every license found must be allowed and packages whose licenses can't be
+// identified are rejected.
+func (lp *LicensePolicy) Check(files []LicenseFile) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no license file found")
+	}
+	ids := LicenseIDs(files)
+	var denied []string
+	for _, id := range ids {
+		if matchesLicense(lp.Deny, id) || (len(lp.Allow) > 0 && !matchesLicense(lp.Allow, id)) {
+			denied = append(denied, id)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("license %s not allowed", strings.Join(denied, ", "))
+	}
+	if len(ids) == 0 && len(lp.Allow) > 0 {
+		return fmt.Errorf("license unknown, only %s allowed", strings.Join(lp.Allow, ", "))
+	}
+	return nil
+}
+
+// matchesLicense reports if a list of policy identifiers includes a license.
+func matchesLicense(ids []string, id string) bool {
+	for _, policyID := range ids {
+		if license.Same(policyID, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package download
+
+import "testing"
+
+func TestLicensePolicy(t *testing.T) {
+	var (
+		mit     = []LicenseFile{{Path: "LICENSE", Licenses: []string{"MIT"}}}
+		gpl     = []LicenseFile{{Path: "COPYING", Licenses: []string{"GPL-3.0-only"}}}
+		unknown = []LicenseFile{{Path: "LICENSE"}}
+	)
+	tests := []struct {
+		policy  LicensePolicy
+		files   []LicenseFile
+		wantErr bool
+	}{
+		{LicensePolicy{}, mit, false},
+		{LicensePolicy{}, nil, true},
+		{LicensePolicy{}, unknown, false},
+		{LicensePolicy{Deny: []string{"GPL-3.0"}}, mit, false},
+		{LicensePolicy{Deny: []string{"GPL-3.0"}}, gpl, true},
+		{LicensePolicy{Deny: []string{"GPL-3.0"}}, append(mit, gpl...), true},
+		{LicensePolicy{Allow: []string{"MIT"}}, mit, false},
+		{LicensePolicy{Allow: []string{"MIT"}}, gpl, true},
+		{LicensePolicy{Allow: []string{"MIT"}}, unknown, true},
+		{LicensePolicy{Deny: []string{"GPL-3.0-only"}}, gpl, true},
+		{LicensePolicy{Deny: []string{"GPL-3.0-or-later"}}, gpl, true},
+		{LicensePolicy{Deny: []string{"LGPL-3.0-only"}}, gpl, false},
+		{LicensePolicy{Allow: []string{"GPL-3.0-or-later"}}, gpl, false},
+	}
+	for i, test := range tests {
+		err := test.policy.Check(test.files)
+		if (err != nil) != test.wantErr {
+			t.Errorf("case %d: expected error=%t, got %v", i, test.wantErr, err)
+		}
+	}
+}
@@ -1,85 +1,71 @@
 package download
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/Masterminds/vcs"
-	"go4.org/lock"
 
 	"github.com/ericchiang/godl/internal/forked/glideutil"
 )
 
-// Cache provides a space for downloading packages.
-type Cache interface {
-	// Dir maps a remote repo to a directory.
-	Dir(remote string, f func(dir string) error) error
-	// Clear removes all cached packages from disk.
-	Clear() error
-}
-
-// NewCache returns a repo for a cache.
-func NewCache(dir string) Cache { return cacheDir{dir} }
-
-// NoCache is a cache implementation that doesn't cache anything.
-var NoCache Cache = tempDir{}
-
-type tempDir struct{}
-
-func (t tempDir) Dir(remote string, f func(dir string) error) error {
-	dir, err := ioutil.TempDir("", "")
-	if err != nil {
-		return err
-	}
-	defer os.RemoveAll(dir)
-	return f(dir)
-}
-
-func (t tempDir) Clear() error { return nil }
-
-// cacheDir is a cache implementation that returns returns a new
-type cacheDir struct {
-	dir string
+// PackageDir returns the directory a package is vendored to.
+func (p *Project) PackageDir(importPath string) string {
+	return filepath.Join(p.Dir, "vendor", filepath.FromSlash(importPath))
 }
 
-func (c cacheDir) Clear() error {
-	return os.RemoveAll(c.dir)
+// Remove deletes a package from the vendor directory of a project.
+func (p *Project) Remove(importPath string) error {
+	return os.RemoveAll(p.PackageDir(importPath))
 }
 
-func (c cacheDir) Dir(remote string, f func(dir string) error) error {
-	h := sha256.New()
-	io.WriteString(h, remote)
-	hash := hex.EncodeToString(h.Sum(nil))
-
-	dir := filepath.Join(c.dir, "src", hash)
-
-	lockFile := dir + ".lock"
-	if err := os.MkdirAll(filepath.Dir(lockFile), 0755); err != nil {
-		return err
+// VendoredPackages returns the import paths of every directory in the project's
+// vendor directory that holds Go files, sorted. If the vendor directory doesn't
+// exist, no packages are returned.
+func (p *Project) VendoredPackages() ([]string, error) {
+	vendorDir := filepath.Join(p.Dir, "vendor")
+	if _, err := os.Stat(vendorDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	closer, err := lock.Lock(lockFile)
+	var pkgs []string
+	seen := make(map[string]bool)
+	err := filepath.Walk(vendorDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != vendorDir && ignore(info) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isGoFile(info.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(vendorDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		pkg := filepath.ToSlash(rel)
+		if !seen[pkg] {
+			seen[pkg] = true
+			pkgs = append(pkgs, pkg)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("could not create lock file for remote %s, is another process downloading that package? (%v)", remote, err)
+		return nil, err
 	}
-	defer closer.Close()
-
-	return f(dir)
-}
-
-func (p *Project) packagePath(importPath string) string {
-	return filepath.Join(p.Dir, "vendor", filepath.FromSlash(importPath))
-}
-
-// Remove deletes a package from the vendor directory of a project.
-func (p *Project) Remove(importPath string) error {
-	return os.RemoveAll(p.packagePath(importPath))
+	sort.Strings(pkgs)
+	return pkgs, nil
 }
 
 // Download downloads a package to the vendor directory of a project.
@@ -99,14 +85,11 @@ func (p *Project) Download(pkg ManifestPackage) (LockPackage, error) {
 	}
 
 	l.Remote = pkg.Remote
-	remote := pkg.Remote
-	if remote == "" {
-		remote = "https://" + pkg.Package
-	}
+	remote := RemoteOf(pkg)
 
 	l.Subpackages = pkg.Subpackages
 
-	dest := p.packagePath(pkg.Package)
+	dest := p.PackageDir(pkg.Package)
 	err = p.Cache.Dir(remote, func(cachePath string) error {
 		repo, err := vcs.NewRepo(remote, cachePath)
 		if err != nil {
@@ -117,6 +100,9 @@ func (p *Project) Download(pkg ManifestPackage) (LockPackage, error) {
 			return fmt.Errorf("download repo: %v", err)
 		}
 		l.Version = version
+		if rev, err := repo.Version(); err == nil && rev != version {
+			l.Revision = rev
+		}
 		if err := os.RemoveAll(dest); err != nil {
 			return fmt.Errorf("clearing existing path in vendor directory: %v", err)
 		}
@@ -125,7 +111,7 @@ func (p *Project) Download(pkg ManifestPackage) (LockPackage, error) {
 			return fmt.Errorf("creating target directory: %v", err)
 		}
 
-		if err := copySubpackages(dest, cachePath, pkg); err != nil {
+		if l.Hash, err = copySubpackages(dest, cachePath, pkg); err != nil {
 			return fmt.Errorf("copying files: %v", err)
 		}
 		return nil
@@ -137,6 +123,71 @@ func (p *Project) Download(pkg ManifestPackage) (LockPackage, error) {
 	return l, nil
 }
 
+// RemoteOf returns the remote to download a package from.
+func RemoteOf(pkg ManifestPackage) string {
+	if pkg.Remote != "" {
+		return pkg.Remote
+	}
+	return "https://" + pkg.Package
+}
+
+// Resolve fetches the latest changes of a package's remote and returns the
+// revision its version currently refers to. Floating reports if the version
+// is empty or names a branch, and so can move. The vendor directory isn't
+// modified.
+func (p *Project) Resolve(pkg ManifestPackage) (rev string, floating bool, err error) {
+	remote := RemoteOf(pkg)
+	err = p.Cache.Dir(remote, func(cachePath string) error {
+		repo, err := vcs.NewRepo(remote, cachePath)
+		if err != nil {
+			return fmt.Errorf("setting up remote: %v", err)
+		}
+		if _, err := downloadRepo(repo, ""); err != nil {
+			return fmt.Errorf("download repo: %v", err)
+		}
+
+		version := pkg.Version
+		if version == "" {
+			version = defaultBranch(repo)
+			floating = true
+		} else {
+			branches, err := repo.Branches()
+			if err != nil {
+				return fmt.Errorf("listing branches: %v", err)
+			}
+			floating = ContainsString(branches, version)
+		}
+
+		if version != "" {
+			if err := repo.UpdateVersion(version); err != nil {
+				return fmt.Errorf("failed to update to version %s of repo: %v", version, err)
+			}
+		}
+		if floating {
+			// Pull the checked out branch.
+			if err := repo.Update(); err != nil {
+				return fmt.Errorf("updating repo: %v", err)
+			}
+		}
+		rev, err = repo.Version()
+		return err
+	})
+	return rev, floating, err
+}
+
+// defaultBranch returns the branch checked out when cloning a repo, or an empty
+// string if it can't be determined.
+func defaultBranch(repo vcs.Repo) string {
+	if repo.Vcs() != vcs.Git {
+		return ""
+	}
+	out, err := repo.RunFromDir("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+}
+
 func downloadRepo(repo vcs.Repo, version string) (string, error) {
 	if !repo.CheckLocal() {
 		if err := repo.Get(); err != nil {
@@ -163,3 +214,13 @@ func downloadRepo(repo vcs.Repo, version string) (string, error) {
 	}
 	return version, nil
 }
+
+// ContainsString reports if s holds str.
+func ContainsString(s []string, str string) bool {
+	for _, ele := range s {
+		if ele == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package download
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestVendoredPackages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []testfile{
+		{"main.go", "package main"},
+		{"vendor/github.com/a/b/b.go", "package b"},
+		{"vendor/github.com/a/b/LICENSE", ""},
+		{"vendor/github.com/a/b/c/c.go", "package c"},
+		{"vendor/github.com/a/b/z.go", "package b"},
+		{"vendor/github.com/a/b/c/d/README.md", ""}, // No Go files.
+		{"vendor/github.com/a/b-c/bc.go", "package bc"},
+		{"vendor/github.com/a/b/.git/hooks/x.go", "package x"}, // Hidden directory.
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Project{Dir: dir}
+	got, err := p.VendoredPackages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"github.com/a/b", "github.com/a/b-c", "github.com/a/b/c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected packages %q got %q", want, got)
+	}
+}
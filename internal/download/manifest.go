@@ -18,6 +18,9 @@ const (
 // Manifest is the manifest file serialization format.
 type Manifest struct {
 	Import []ManifestPackage `json:"import,omitempty"`
+	// Licenses restricts the licenses of dependencies. It's checked when
+	// vendoring.
+	Licenses *LicensePolicy `json:"licenses,omitempty"`
 }
 
 // ManifestPackage is the manifest file serialization of a package.
@@ -35,10 +38,23 @@ type Lock struct {
 
 // LockPackage is the lock file serialization of a package.
 type LockPackage struct {
-	Package     string   `json:"name"`
-	Version     string   `json:"version"`
+	Package string `json:"name"`
+	Version string `json:"version"`
+	// Revision is the commit the version resolved to when the package was
+	// downloaded. It's omitted if the version is already a revision.
+	Revision    string   `json:"revision,omitempty"`
 	Remote      string   `json:"remote,omitempty"`
 	Subpackages []string `json:"subpackage,omitempty"`
+	// Hash is the hash of the package's files in the vendor directory.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Rev returns the revision the package was downloaded at.
+func (l LockPackage) Rev() string {
+	if l.Revision != "" {
+		return l.Revision
+	}
+	return l.Version
 }
 
 // Project can be used to manage manifest and lock files.
@@ -58,12 +74,50 @@ func (p *Project) Import(m *Manifest) error {
 	return write(path, m)
 }
 
+// Init creates the manifest and lock files. Neither must already exist.
+func (p *Project) Init(m *Manifest, l *Lock) error {
+	for _, file := range []string{manifestFile, lockFile} {
+		if _, err := os.Stat(filepath.Join(p.Dir, file)); err == nil {
+			return fmt.Errorf("%s already exists", file)
+		}
+	}
+	if err := write(filepath.Join(p.Dir, manifestFile), m); err != nil {
+		return err
+	}
+	sortLock(l)
+	return write(filepath.Join(p.Dir, lockFile), l)
+}
+
 // LoadManifest reads and parses the project's manifest file.
 func (p *Project) LoadManifest() (*Manifest, error) {
 	var m Manifest
 	return &m, load(filepath.Join(p.Dir, manifestFile), &m)
 }
 
+// UpdateManifest reads the manifest file, applies the passed function, then writes
+// the result. If the manifest doesn't exist, the function is passed an empty one.
+// Comments and formatting of the existing file are kept where possible, see
+// editManifest.
+func (p *Project) UpdateManifest(f func(m *Manifest) error) error {
+	var m Manifest
+	path := filepath.Join(p.Dir, manifestFile)
+	original, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(original, &m); err != nil {
+		return err
+	}
+	if err := f(&m); err != nil {
+		return err
+	}
+	data, err := editManifest(original, &m)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
 // LoadLock reads and parses the project's lock file. If it doesn't exist, an empty
 // lock file is returned.
 func (p *Project) LoadLock() (*Lock, error) {
@@ -87,6 +141,12 @@ func (p *Project) UpdateLock(f func(l *Lock) error) error {
 	if err := f(l); err != nil {
 		return err
 	}
+	sortLock(l)
+	return write(filepath.Join(p.Dir, lockFile), l)
+}
+
+// sortLock orders packages and subpackages so lock files diff cleanly.
+func sortLock(l *Lock) {
 	for i, p := range l.Import {
 		sort.Strings(p.Subpackages)
 		l.Import[i] = p
@@ -94,7 +154,6 @@ func (p *Project) UpdateLock(f func(l *Lock) error) error {
 	sort.Slice(l.Import, func(i, j int) bool {
 		return l.Import[i].Package < l.Import[j].Package
 	})
-	return write(filepath.Join(p.Dir, lockFile), l)
 }
 
 func load(filepath string, i interface{}) error {
@@ -0,0 +1,94 @@
+package download
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/vcs"
+)
+
+// Outdated describes how far a locked package is behind its remote.
+type Outdated struct {
+	Package  string `json:"package"`
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+	// RevisionDate is the commit date of the locked revision.
+	RevisionDate time.Time `json:"revisionDate"`
+
+	// LatestTag is the newest semantic version tag of the remote, if any.
+	LatestTag string `json:"latestTag,omitempty"`
+	// DefaultBranch is the branch checked out when cloning the remote.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	// CommitsBehind is the number of commits on the default branch that aren't
+	// part of the locked revision, or -1 if it can't be determined.
+	CommitsBehind int `json:"commitsBehind"`
+}
+
+// Outdated fetches the remote of a locked package and compares it against the
+// locked revision. The vendor directory isn't modified.
+func (p *Project) Outdated(pkg LockPackage) (*Outdated, error) {
+	o := &Outdated{
+		Package:       pkg.Package,
+		Version:       pkg.Version,
+		Revision:      pkg.Rev(),
+		CommitsBehind: -1,
+	}
+
+	remote := RemoteOf(ManifestPackage{Package: pkg.Package, Remote: pkg.Remote})
+	err := p.Cache.Dir(remote, func(cachePath string) error {
+		repo, err := vcs.NewRepo(remote, cachePath)
+		if err != nil {
+			return fmt.Errorf("setting up remote: %v", err)
+		}
+		if _, err := downloadRepo(repo, ""); err != nil {
+			return fmt.Errorf("download repo: %v", err)
+		}
+
+		tags, err := repo.Tags()
+		if err != nil {
+			return fmt.Errorf("listing tags: %v", err)
+		}
+		o.LatestTag = latestSemver(tags)
+
+		branches, err := repo.Branches()
+		if err != nil {
+			return fmt.Errorf("listing branches: %v", err)
+		}
+		if branch := defaultBranch(repo); ContainsString(branches, branch) {
+			o.DefaultBranch = branch
+			o.CommitsBehind = commitsBehind(repo, o.Revision, branch)
+		}
+
+		// Read the commit of the locked revision without checking it out, so
+		// the cached checkout is left as it was.
+		ci, err := repo.CommitInfo(o.Revision)
+		if err != nil {
+			return fmt.Errorf("revision %s: %v", o.Revision, err)
+		}
+		o.RevisionDate = ci.Date
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// commitsBehind counts the commits on a remote branch that aren't reachable
+// from rev. Only git repos are supported, other repos return -1.
+func commitsBehind(repo vcs.Repo, rev, branch string) int {
+	if repo.Vcs() != vcs.Git {
+		return -1
+	}
+	out, err := repo.RunFromDir("git", "rev-list", "--count", rev+"..origin/"+branch)
+	if err != nil {
+		return -1
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+package download
+
+import (
+	"os"
+	"sort"
+)
+
+// Action is an operation a plan performs on a single package.
+type Action int
+
+// Actions a plan can perform.
+const (
+	// Add downloads a package that is in the manifest but not the lock file.
+	Add Action = iota
+	// Update re-downloads a package whose manifest and lock entries differ.
+	Update
+	// Remove deletes a package that is in the lock file but not the manifest.
+	Remove
+	// Restore re-downloads a locked package missing from the vendor directory.
+	Restore
+)
+
+func (a Action) String() string {
+	switch a {
+	case Add:
+		return "add"
+	case Update:
+		return "update"
+	case Remove:
+		return "remove"
+	case Restore:
+		return "restore"
+	}
+	return "unknown"
+}
+
+// Fields of a package that can differ between the manifest and lock file.
+const (
+	FieldVersion     = "version"
+	FieldRemote      = "remote"
+	FieldSubpackages = "subpackages"
+)
+
+// Change is a single step of a plan.
+type Change struct {
+	Action  Action
+	Package string
+
+	// Manifest is the desired state of the package. It's nil for removals.
+	Manifest *ManifestPackage
+	// Lock is the current state of the package. It's nil for additions.
+	Lock *LockPackage
+
+	// Fields lists the fields that differ between the manifest and lock file
+	// for updates.
+	Fields []string
+}
+
+// Plan is the set of changes required to bring the lock file and vendor
+// directory in line with the manifest.
+type Plan struct {
+	Changes []Change
+
+	// Untracked holds packages in the vendor directory that aren't part of any
+	// package in the lock file. Plans never modify them.
+	Untracked []string
+}
+
+// Plan compares the manifest, lock file and vendor directory of a project.
+func (p *Project) Plan() (*Plan, error) {
+	m, err := p.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+	l, err := p.LoadLock()
+	if err != nil {
+		return nil, err
+	}
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := NewPlan(m, l, func(pkg string) bool {
+		_, err := os.Stat(p.PackageDir(pkg))
+		return err == nil
+	})
+
+	for _, pkg := range vendored {
+		tracked := false
+		for _, lockPkg := range l.Import {
+			if InPackage(pkg, lockPkg.Package) {
+				tracked = true
+				break
+			}
+		}
+		if !tracked {
+			plan.Untracked = append(plan.Untracked, pkg)
+		}
+	}
+	return plan, nil
+}
+
+// NewPlan computes the changes required to apply a manifest to a lock file.
+// The isVendored function reports if a locked package exists on disk. Changes
+// are ordered by package name.
+func NewPlan(m *Manifest, l *Lock, isVendored func(pkg string) bool) *Plan {
+	locked := make(map[string]LockPackage)
+	for _, pkg := range l.Import {
+		locked[pkg.Package] = pkg
+	}
+
+	plan := new(Plan)
+	inManifest := make(map[string]bool)
+	for _, pkg := range m.Import {
+		pkg := pkg
+		inManifest[pkg.Package] = true
+
+		lockPkg, ok := locked[pkg.Package]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{
+				Action:   Add,
+				Package:  pkg.Package,
+				Manifest: &pkg,
+			})
+			continue
+		}
+
+		c := Change{Package: pkg.Package, Manifest: &pkg, Lock: &lockPkg}
+		if c.Fields = DiffPackages(lockPkg, pkg); len(c.Fields) > 0 {
+			c.Action = Update
+		} else if !isVendored(pkg.Package) {
+			c.Action = Restore
+		} else {
+			continue
+		}
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	for _, pkg := range l.Import {
+		pkg := pkg
+		if !inManifest[pkg.Package] {
+			plan.Changes = append(plan.Changes, Change{
+				Action:  Remove,
+				Package: pkg.Package,
+				Lock:    &pkg,
+			})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Package < plan.Changes[j].Package
+	})
+	return plan
+}
+
+// DiffPackages returns the fields that differ between a lock file and manifest
+// entry. The order of subpackages is ignored, and a manifest entry without a
+// version matches any locked version.
+func DiffPackages(l LockPackage, m ManifestPackage) []string {
+	var fields []string
+	if m.Version != "" && l.Version != m.Version {
+		fields = append(fields, FieldVersion)
+	}
+	if l.Remote != m.Remote {
+		fields = append(fields, FieldRemote)
+	}
+	if !stringSetEq(l.Subpackages, m.Subpackages) {
+		fields = append(fields, FieldSubpackages)
+	}
+	return fields
+}
+
+func stringSetEq(s1, s2 []string) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	s1 = append([]string{}, s1...)
+	s2 = append([]string{}, s2...)
+	sort.Strings(s1)
+	sort.Strings(s2)
+	for i, s := range s1 {
+		if s2[i] != s {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package download
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPlan(t *testing.T) {
+	m := &Manifest{
+		Import: []ManifestPackage{
+			{Package: "github.com/a/new", Version: "v1.0.0"},
+			{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"x", "y"}},
+			{Package: "github.com/a/version", Version: "v2.0.0"},
+			{Package: "github.com/a/remote", Version: "v1.0.0", Remote: "git@github.com:a/remote.git"},
+			{Package: "github.com/a/subpackages", Version: "v1.0.0", Subpackages: []string{"x"}},
+			{Package: "github.com/a/latest"},
+			{Package: "github.com/a/missing", Version: "v1.0.0"},
+		},
+	}
+	l := &Lock{
+		Import: []LockPackage{
+			{Package: "github.com/a/latest", Version: "fd4f2ed49e8aa8b2b3c1d4f1a5d0ee68f1a6f1bc"},
+			{Package: "github.com/a/missing", Version: "v1.0.0"},
+			{Package: "github.com/a/old", Version: "v1.0.0"},
+			{Package: "github.com/a/remote", Version: "v1.0.0"},
+			{Package: "github.com/a/same", Version: "v1.0.0", Subpackages: []string{"y", "x"}},
+			{Package: "github.com/a/subpackages", Version: "v1.0.0"},
+			{Package: "github.com/a/version", Version: "v1.0.0"},
+		},
+	}
+	isVendored := func(pkg string) bool { return pkg != "github.com/a/missing" }
+
+	plan := NewPlan(m, l, isVendored)
+
+	type change struct {
+		action Action
+		pkg    string
+		fields []string
+	}
+	want := []change{
+		{Restore, "github.com/a/missing", nil},
+		{Add, "github.com/a/new", nil},
+		{Remove, "github.com/a/old", nil},
+		{Update, "github.com/a/remote", []string{FieldRemote}},
+		{Update, "github.com/a/subpackages", []string{FieldSubpackages}},
+		{Update, "github.com/a/version", []string{FieldVersion}},
+	}
+	var got []change
+	for _, c := range plan.Changes {
+		got = append(got, change{c.Action, c.Package, c.Fields})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected changes %v got %v", want, got)
+	}
+}
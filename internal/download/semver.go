@@ -0,0 +1,106 @@
+package download
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. Build metadata is dropped.
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseSemver parses tags such as "v1.2.3", "1.2" or "v2.0.0-rc.1". Missing
+// minor and patch numbers default to zero. Tags without a "v" prefix need at
+// least a minor number, so numbers such as dates or revisions made of digits
+// aren't taken as versions.
+func parseSemver(s string) (semver, bool) {
+	var v semver
+	prefixed := strings.HasPrefix(s, "v")
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+		if v.pre == "" {
+			return v, false
+		}
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 || (len(parts) < 2 && !prefixed) {
+		return v, false
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		*nums[i] = n
+	}
+	return v, true
+}
+
+// canonicalSemver parses tags in the canonical "vX.Y.Z" form, with an optional
+// pre-release, such as "v2.0.0-rc.1". Other tags, and revisions made only of
+// digits, aren't accepted.
+func canonicalSemver(tag string) (semver, bool) {
+	v, ok := parseSemver(tag)
+	if !ok || !strings.HasPrefix(tag, "v") || strings.Contains(tag, "+") {
+		return v, false
+	}
+	canonical := fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	if v.pre != "" {
+		canonical += "-" + v.pre
+	}
+	return v, canonical == tag
+}
+
+// less reports if v has a lower precedence than o. Pre-release identifiers are
+// compared as strings, which is enough to order tags like "rc.1" and "rc.2".
+func (v semver) less(o semver) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	if v.patch != o.patch {
+		return v.patch < o.patch
+	}
+	if v.pre == "" || o.pre == "" {
+		return v.pre != "" && o.pre == ""
+	}
+	return v.pre < o.pre
+}
+
+// latestSemver returns the tag with the highest semantic version. Pre-releases
+// are only considered if there are no other tags.
+func latestSemver(tags []string) string {
+	var (
+		latest, latestPre   string
+		latestV, latestPreV semver
+	)
+	for _, tag := range tags {
+		v, ok := parseSemver(tag)
+		if !ok {
+			continue
+		}
+		if v.pre != "" {
+			if latestPre == "" || latestPreV.less(v) {
+				latestPre, latestPreV = tag, v
+			}
+			continue
+		}
+		if latest == "" || latestV.less(v) {
+			latest, latestV = tag, v
+		}
+	}
+	if latest == "" {
+		return latestPre
+	}
+	return latest
+}
@@ -0,0 +1,24 @@
+package download
+
+import "testing"
+
+func TestLatestSemver(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"foo", "release-1"}, ""},
+		{[]string{"v1.0.0", "v1.10.0", "v1.9.0"}, "v1.10.0"},
+		{[]string{"v1.0.0", "v2.0.0-rc.1"}, "v1.0.0"},
+		{[]string{"v2.0.0-rc.1", "v2.0.0-rc.2"}, "v2.0.0-rc.2"},
+		{[]string{"1.2", "v1.1.5"}, "1.2"},
+		{[]string{"v1.4.0", "20190401", "1234567"}, "v1.4.0"},
+		{[]string{"v1", "1"}, "v1"},
+	}
+	for _, test := range tests {
+		if got := latestSemver(test.tags); got != test.want {
+			t.Errorf("latestSemver(%q), want=%q, got=%q", test.tags, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package download
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportChains returns a shortest chain of imports from each of the project's
+// main or top-level packages to pkg, or a package within it. Top-level packages
+// are those not imported by other project packages. Chains start with the
+// project package and are sorted. Test files aren't considered.
+func (p *Project) ImportChains(pkg string) ([][]string, error) {
+	g, err := p.ProjectGraph()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := p.entryPackages(g)
+	if err != nil {
+		return nil, err
+	}
+	vendorGraph, err := p.VendorImports()
+	if err != nil {
+		return nil, err
+	}
+	for vendoredPkg, imports := range vendorGraph {
+		g[vendoredPkg] = imports
+	}
+
+	var chains [][]string
+	for _, entry := range entries {
+		if chain := shortestChain(g, entry, pkg); chain != nil {
+			chains = append(chains, chain)
+		}
+	}
+	return chains, nil
+}
+
+// shortestChain searches the graph breadth first for a chain of imports from
+// a package to target, or a package within it.
+func shortestChain(g ImportGraph, from, target string) []string {
+	parent := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if InPackage(pkg, target) {
+			var chain []string
+			for ; pkg != ""; pkg = parent[pkg] {
+				chain = append([]string{pkg}, chain...)
+			}
+			return chain
+		}
+		for _, importPath := range g[pkg] {
+			if _, ok := parent[importPath]; !ok {
+				parent[importPath] = pkg
+				queue = append(queue, importPath)
+			}
+		}
+	}
+	return nil
+}
+
+// entryPackages returns the project's main packages and the packages no other
+// project package imports, sorted.
+func (p *Project) entryPackages(g ImportGraph) ([]string, error) {
+	if len(g) == 0 {
+		return nil, nil
+	}
+	imported := make(map[string]bool)
+	for _, imports := range g {
+		for _, importPath := range imports {
+			imported[importPath] = true
+		}
+	}
+	pkgPath, err := p.importPathDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for pkg := range g {
+		isMainPkg, err := isMain(pkgPath(pkg))
+		if err != nil {
+			return nil, err
+		}
+		if isMainPkg || !imported[pkg] {
+			entries = append(entries, pkg)
+		}
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
+
+// ReachablePackages returns the vendored packages imported, directly or not,
+// by the project's packages. Test files aren't considered.
+func (p *Project) ReachablePackages() (map[string]bool, error) {
+	g, err := p.ProjectGraph()
+	if err != nil {
+		return nil, err
+	}
+	reachable := make(map[string]bool)
+	if len(g) == 0 {
+		return reachable, nil
+	}
+	vendored, err := p.VendoredPackages()
+	if err != nil {
+		return nil, err
+	}
+	isVendored := make(map[string]bool)
+	for _, pkg := range vendored {
+		isVendored[pkg] = true
+	}
+
+	visited := make(map[string]bool)
+	visit := func(pkg string) (bool, error) {
+		_, isProject := g[pkg]
+		if visited[pkg] || !(isProject || isVendored[pkg]) {
+			return false, nil
+		}
+		visited[pkg] = true
+		if isVendored[pkg] {
+			reachable[pkg] = true
+		}
+		return true, nil
+	}
+	pkgPath, err := p.importPathDir()
+	if err != nil {
+		return nil, err
+	}
+	for pkg := range g {
+		if err := walkImports(pkg, pkgPath, visit); err != nil {
+			return nil, err
+		}
+	}
+	return reachable, nil
+}
+
+// importPathDir returns a function resolving the import paths of project
+// packages to the project directory and other import paths to the vendor
+// directory.
+func (p *Project) importPathDir() (func(pkg string) string, error) {
+	root, err := p.ImportPath()
+	if err != nil {
+		return nil, err
+	}
+	return func(pkg string) string {
+		if InPackage(pkg, root) {
+			rel := strings.TrimPrefix(strings.TrimPrefix(pkg, root), "/")
+			return filepath.Join(p.Dir, filepath.FromSlash(rel))
+		}
+		return p.PackageDir(pkg)
+	}, nil
+}
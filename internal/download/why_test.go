@@ -0,0 +1,89 @@
+package download
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestImportChains(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []testfile{
+		{"go.mod", "module example.com/app\n"},
+		{"cmd/server/main.go", `package main
+
+		import (
+			"example.com/app/api"
+			"example.com/app/store"
+		)
+		`},
+		{"cmd/tool/main.go", `package main
+
+		import "github.com/a/b"
+		`},
+		{"api/api.go", `package api
+
+		import "example.com/app/store"
+		`},
+		{"store/store.go", `package store
+
+		import "github.com/a/b/c"
+		`},
+		{"store/store_test.go", `package store
+
+		import "github.com/d/e"
+		`},
+		{"util/util.go", "package util"},
+		{"vendor/github.com/a/b/b.go", `package b
+
+		import "github.com/a/b/c"
+		`},
+		{"vendor/github.com/a/b/c/c.go", "package c"},
+		{"vendor/github.com/a/b/unused/unused.go", "package unused"},
+		{"vendor/github.com/d/e/e.go", "package e"},
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	p := &Project{Dir: dir}
+
+	tests := []struct {
+		pkg  string
+		want [][]string
+	}{
+		{"github.com/a/b/c", [][]string{
+			{"example.com/app/cmd/server", "example.com/app/store", "github.com/a/b/c"},
+			{"example.com/app/cmd/tool", "github.com/a/b", "github.com/a/b/c"},
+		}},
+		{"github.com/a/b", [][]string{
+			{"example.com/app/cmd/server", "example.com/app/store", "github.com/a/b/c"},
+			{"example.com/app/cmd/tool", "github.com/a/b"},
+		}},
+		{"github.com/d/e", nil},
+	}
+	for _, test := range tests {
+		got, err := p.ImportChains(test.pkg)
+		if err != nil {
+			t.Errorf("import chains of %s: %v", test.pkg, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("import chains of %s: want=%q, got=%q", test.pkg, test.want, got)
+		}
+	}
+
+	reachable, err := p.ReachablePackages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"github.com/a/b": true, "github.com/a/b/c": true}
+	if !reflect.DeepEqual(reachable, want) {
+		t.Errorf("reachable packages: want=%v, got=%v", want, reachable)
+	}
+}
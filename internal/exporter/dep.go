@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"io"
+	"log"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// exportDep writes a dep Gopkg.lock file. Versions that look like tags are
+// recorded as versions, others as branches. The solve metadata dep uses to
+// detect changes to Gopkg.toml is omitted.
+func exportDep(w io.Writer, _ *download.Project, l *download.Lock, logger *log.Logger) error {
+	type project struct {
+		Name     string   `toml:"name"`
+		Branch   string   `toml:"branch,omitempty"`
+		Packages []string `toml:"packages"`
+		Revision string   `toml:"revision"`
+		Source   string   `toml:"source,omitempty"`
+		Version  string   `toml:"version,omitempty"`
+	}
+	var lock struct {
+		Projects []project `toml:"projects"`
+	}
+
+	for _, pkg := range l.Import {
+		p := project{
+			Name:     pkg.Package,
+			Packages: append([]string{"."}, pkg.Subpackages...),
+			Revision: pkg.Rev(),
+			Source:   pkg.Remote,
+		}
+		if t := tag(pkg); isTag(t) {
+			p.Version = t
+		} else {
+			p.Branch = t
+		}
+		lock.Projects = append(lock.Projects, p)
+	}
+	return toml.NewEncoder(w).Encode(lock)
+}
+
+// isTag reports if a version looks like a version tag, such as "v1.2.0" or
+// "1.2".
+func isTag(version string) bool {
+	for i, r := range version {
+		if i == 0 && r == 'v' {
+			continue
+		}
+		return unicode.IsDigit(r)
+	}
+	return false
+}
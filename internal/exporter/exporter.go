@@ -0,0 +1,82 @@
+// Package exporter converts godl lock files into the files of other Go
+// dependency management tools.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// Exporter writes a lock file in the format of another tool.
+type Exporter interface {
+	// Format is the name used to select the exporter.
+	Format() string
+	// File is the slash separated path the tool expects its file at, relative
+	// to the project directory.
+	File() string
+	// Export writes the lock file. The project is used by formats that record
+	// details of the project itself, such as its import path. Information the
+	// format can't represent, such as remotes for some tools, is logged as a
+	// warning.
+	Export(w io.Writer, p *download.Project, l *download.Lock, logger *log.Logger) error
+}
+
+// exportFunc writes a lock file in the format of another tool.
+type exportFunc func(w io.Writer, p *download.Project, l *download.Lock, logger *log.Logger) error
+
+// fileExporter is an exporter for a file at a fixed location of a project.
+type fileExporter struct {
+	format string
+	file   string
+	export exportFunc
+}
+
+func (f fileExporter) Format() string { return f.format }
+
+func (f fileExporter) File() string { return f.file }
+
+func (f fileExporter) Export(w io.Writer, p *download.Project, l *download.Lock, logger *log.Logger) error {
+	return f.export(w, p, l, logger)
+}
+
+// Exporters holds every supported format.
+var Exporters = []Exporter{
+	fileExporter{format: "godeps", file: "Godeps/Godeps.json", export: exportGodeps},
+	fileExporter{format: "glide", file: "glide.lock", export: exportGlide},
+	fileExporter{format: "dep", file: "Gopkg.lock", export: exportDep},
+	fileExporter{format: "vendor.conf", file: "vendor.conf", export: exportVendorConf},
+}
+
+// ForFormat returns the exporter with the given format name.
+func ForFormat(format string) (Exporter, error) {
+	var formats []string
+	for _, e := range Exporters {
+		if e.Format() == format {
+			return e, nil
+		}
+		formats = append(formats, e.Format())
+	}
+	return nil, fmt.Errorf("unsupported format %q, supported formats are %s", format, strings.Join(formats, ", "))
+}
+
+// importPaths returns the import paths of a package's root and subpackages.
+func importPaths(pkg download.LockPackage) []string {
+	paths := []string{pkg.Package}
+	for _, subPkg := range pkg.Subpackages {
+		paths = append(paths, pkg.Package+"/"+subPkg)
+	}
+	return paths
+}
+
+// tag returns the version of a package if it names something other than the
+// locked revision, such as a tag or branch.
+func tag(pkg download.LockPackage) string {
+	if pkg.Version == pkg.Rev() {
+		return ""
+	}
+	return pkg.Version
+}
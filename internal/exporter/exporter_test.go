@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+var testLock = &download.Lock{
+	Import: []download.LockPackage{
+		{
+			Package:     "github.com/foo/bar",
+			Version:     "v1.2.0",
+			Revision:    "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			Subpackages: []string{"baz"},
+		},
+		{
+			Package: "github.com/foo/private",
+			Version: "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e",
+			Remote:  "git@github.com:foo/private.git",
+		},
+	},
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "godeps",
+			want: `{
+	"ImportPath": "github.com/me/project",
+	"GoVersion": "go1.12",
+	"Deps": [
+		{
+			"ImportPath": "github.com/foo/bar",
+			"Comment": "v1.2.0",
+			"Rev": "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f"
+		},
+		{
+			"ImportPath": "github.com/foo/bar/baz",
+			"Comment": "v1.2.0",
+			"Rev": "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f"
+		},
+		{
+			"ImportPath": "github.com/foo/private",
+			"Rev": "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e"
+		}
+	]
+}
+`,
+		},
+		{
+			format: "glide",
+			want: `imports:
+- name: github.com/foo/bar
+  subpackages:
+  - baz
+  version: 3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f
+- name: github.com/foo/private
+  repo: git@github.com:foo/private.git
+  version: 9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e
+`,
+		},
+		{
+			format: "dep",
+			want: `[[projects]]
+  name = "github.com/foo/bar"
+  packages = [".", "baz"]
+  revision = "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f"
+  version = "v1.2.0"
+
+[[projects]]
+  name = "github.com/foo/private"
+  packages = ["."]
+  revision = "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e"
+  source = "git@github.com:foo/private.git"
+`,
+		},
+		{
+			format: "vendor.conf",
+			want: `github.com/foo/bar 3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f
+github.com/foo/private 9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e git@github.com:foo/private.git
+`,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	goMod := "module github.com/me/project\n\ngo 1.12\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := &download.Project{Dir: dir}
+
+	logger := log.New(ioutil.Discard, "", 0)
+	for _, test := range tests {
+		e, err := ForFormat(test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(bytes.Buffer)
+		if err := e.Export(buf, p, testLock, logger); err != nil {
+			t.Errorf("%s: export: %v", test.format, err)
+			continue
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", test.format, test.want, got)
+		}
+	}
+}
+
+func TestForFormat(t *testing.T) {
+	if _, err := ForFormat("gvt"); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
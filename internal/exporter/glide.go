@@ -0,0 +1,40 @@
+package exporter
+
+import (
+	"io"
+	"log"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// exportGlide writes a glide.lock file pinning each package to its revision.
+// The hash of glide.yaml is omitted, since there's no glide.yaml to hash.
+func exportGlide(w io.Writer, _ *download.Project, l *download.Lock, logger *log.Logger) error {
+	type glidePackage struct {
+		Name        string   `json:"name"`
+		Version     string   `json:"version"`
+		Repo        string   `json:"repo,omitempty"`
+		Subpackages []string `json:"subpackages,omitempty"`
+	}
+	lock := struct {
+		Imports []glidePackage `json:"imports"`
+	}{Imports: []glidePackage{}}
+
+	for _, pkg := range l.Import {
+		lock.Imports = append(lock.Imports, glidePackage{
+			Name:        pkg.Package,
+			Version:     pkg.Rev(),
+			Repo:        pkg.Remote,
+			Subpackages: pkg.Subpackages,
+		})
+	}
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,77 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// exportGodeps writes a godep Godeps/Godeps.json file. Each subpackage is
+// listed as its own dependency, with tags recorded as comments. godep has no
+// notion of remotes.
+func exportGodeps(w io.Writer, p *download.Project, l *download.Lock, logger *log.Logger) error {
+	importPath, err := p.ImportPath()
+	if err != nil {
+		return err
+	}
+	goVersion, err := godepsGoVersion(p)
+	if err != nil {
+		return err
+	}
+
+	type dep struct {
+		ImportPath string
+		Comment    string `json:",omitempty"`
+		Rev        string
+	}
+	godeps := struct {
+		ImportPath string
+		GoVersion  string
+		Deps       []dep
+	}{ImportPath: importPath, GoVersion: goVersion, Deps: []dep{}}
+
+	for _, pkg := range l.Import {
+		if pkg.Remote != "" {
+			logger.Printf("warning: godep doesn't support remotes, dropping remote %s of %s", pkg.Remote, pkg.Package)
+		}
+		for _, importPath := range importPaths(pkg) {
+			godeps.Deps = append(godeps.Deps, dep{
+				ImportPath: importPath,
+				Comment:    tag(pkg),
+				Rev:        pkg.Rev(),
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(godeps, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// godepsGoVersion returns the Go version to record for the project, such as
+// "go1.12". It's read from the go directive of the project's go.mod file if
+// there is one, otherwise the release of the running Go version is used, as
+// godep does.
+func godepsGoVersion(p *download.Project) (string, error) {
+	mod, err := download.ReadGoMod(filepath.Join(p.Dir, "go.mod"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err == nil && mod.Go != "" {
+		return "go" + mod.Go, nil
+	}
+	version := runtime.Version()
+	if parts := strings.SplitN(version, ".", 3); len(parts) == 3 {
+		version = parts[0] + "." + parts[1]
+	}
+	return version, nil
+}
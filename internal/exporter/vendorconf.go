@@ -0,0 +1,24 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// exportVendorConf writes a vendor.conf file used by vndr and trash. Both tools
+// vendor whole repos, so subpackages aren't listed.
+func exportVendorConf(w io.Writer, _ *download.Project, l *download.Lock, logger *log.Logger) error {
+	for _, pkg := range l.Import {
+		line := pkg.Package + " " + pkg.Rev()
+		if pkg.Remote != "" {
+			line += " " + pkg.Remote
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
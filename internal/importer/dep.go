@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// depProject is a [[projects]] entry in a Gopkg.lock file, or a [[constraint]]
+// or [[override]] entry in a Gopkg.toml file.
+type depProject struct {
+	Name     string   `toml:"name"`
+	Source   string   `toml:"source"`
+	Revision string   `toml:"revision"`
+	Version  string   `toml:"version"`
+	Branch   string   `toml:"branch"`
+	Packages []string `toml:"packages"`
+}
+
+// importDep reads a dep Gopkg.toml or Gopkg.lock file. Both files are read if
+// present in the same directory. The lock provides the pinned revisions, while
+// the constraints and overrides of the manifest are only used when there's no
+// lock.
+func importDep(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	manifest = filepath.Clean(manifest)
+	dir := filepath.Dir(manifest)
+
+	// The file passed by the user must exist, its sibling is optional.
+	readFile := func(name string) ([]byte, error) {
+		path := filepath.Join(dir, name)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) && path != manifest {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("read manifest: %v", err)
+		}
+		return data, nil
+	}
+
+	tomlData, err := readFile("Gopkg.toml")
+	if err != nil {
+		return nil, err
+	}
+	lockData, err := readFile("Gopkg.lock")
+	if err != nil {
+		return nil, err
+	}
+	return parseDep(tomlData, lockData, logger)
+}
+
+func parseDep(tomlData, lockData []byte, logger *log.Logger) ([]download.ManifestPackage, error) {
+	var manifest struct {
+		Constraints []depProject `toml:"constraint"`
+		Overrides   []depProject `toml:"override"`
+	}
+	if tomlData != nil {
+		if err := toml.Unmarshal(tomlData, &manifest); err != nil {
+			return nil, fmt.Errorf("parsing Gopkg.toml: %v", err)
+		}
+	}
+
+	if lockData == nil {
+		// Without a lock, use constraints as the list of dependencies. Overrides
+		// take precedence over constraints of the same project.
+		var pkgs []download.ManifestPackage
+		for _, projects := range [][]depProject{manifest.Overrides, manifest.Constraints} {
+			for _, p := range projects {
+				found := false
+				for _, pkg := range pkgs {
+					if pkg.Package == p.Name {
+						found = true
+						break
+					}
+				}
+				if found {
+					continue
+				}
+				pkgs = append(pkgs, download.ManifestPackage{
+					Package: p.Name,
+					Version: depConstraintVersion(p, logger),
+					Remote:  p.Source,
+				})
+			}
+		}
+		return pkgs, nil
+	}
+
+	var lock struct {
+		Projects []depProject `toml:"projects"`
+	}
+	if err := toml.Unmarshal(lockData, &lock); err != nil {
+		return nil, fmt.Errorf("parsing Gopkg.lock: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, p := range lock.Projects {
+		pkg := download.ManifestPackage{
+			Package: p.Name,
+			Version: p.Revision,
+			Remote:  p.Source,
+		}
+		if p.Version != "" {
+			// Prefer tags, the same as other importers.
+			pkg.Version = p.Version
+		}
+		for _, subPkg := range p.Packages {
+			if subPkg != "." {
+				pkg.Subpackages = append(pkg.Subpackages, subPkg)
+			}
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	for _, c := range manifest.Constraints {
+		if isVersionRange(c.Version) {
+			logger.Printf("warning: constraint %s %s isn't enforced by godl, using the locked version", c.Name, c.Version)
+		}
+	}
+	for _, o := range manifest.Overrides {
+		logger.Printf("warning: godl doesn't resolve transitive dependencies, override of %s only applies as a direct dependency", o.Name)
+	}
+	return pkgs, nil
+}
+
+// depConstraintVersion picks the version to download for a Gopkg.toml
+// constraint or override.
+func depConstraintVersion(p depProject, logger *log.Logger) string {
+	switch {
+	case p.Revision != "":
+		return p.Revision
+	case p.Branch != "":
+		return p.Branch
+	case isVersionRange(p.Version):
+		logger.Printf("warning: %s has version range %q, using latest version instead", p.Name, p.Version)
+		return ""
+	}
+	// dep treats bare versions as "^version". Pin to the exact version instead.
+	return strings.TrimPrefix(p.Version, "=")
+}
@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// glidePackage is an entry in a glide.yaml or glide.lock file. glide.yaml files
+// use "package" for the import path, while lock files use "name".
+type glidePackage struct {
+	Package     string   `json:"package"`
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Repo        string   `json:"repo"`
+	Subpackages []string `json:"subpackages"`
+}
+
+// importGlide reads a glide.yaml or glide.lock file. The pinned revisions of a
+// glide.lock are preferred, so if a glide.yaml is passed and a glide.lock
+// exists in the same directory, the lock is used instead.
+func importGlide(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	lockPath := filepath.Join(filepath.Dir(manifest), "glide.lock")
+	if filepath.Base(manifest) == "glide.yaml" {
+		if _, err := os.Stat(lockPath); err == nil {
+			logger.Printf("using pinned revisions from %s", lockPath)
+			manifest = lockPath
+		}
+	}
+
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	if filepath.Base(manifest) == "glide.lock" {
+		return parseGlideLock(data)
+	}
+	return parseGlideYAML(data, logger)
+}
+
+func parseGlideLock(data []byte) ([]download.ManifestPackage, error) {
+	var lock struct {
+		Imports     []glidePackage `json:"imports"`
+		TestImports []glidePackage `json:"testImports"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, pkg := range append(lock.Imports, lock.TestImports...) {
+		pkgs = appendGlidePackage(pkgs, pkg.Name, pkg)
+	}
+	return pkgs, nil
+}
+
+func parseGlideYAML(data []byte, logger *log.Logger) ([]download.ManifestPackage, error) {
+	var manifest struct {
+		Import     []glidePackage `json:"import"`
+		TestImport []glidePackage `json:"testImport"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, pkg := range append(manifest.Import, manifest.TestImport...) {
+		if isVersionRange(pkg.Version) {
+			// godl only downloads exact versions.
+			logger.Printf("warning: %s has version range %q, using latest version instead", pkg.Package, pkg.Version)
+			pkg.Version = ""
+		}
+		pkgs = appendGlidePackage(pkgs, pkg.Package, pkg)
+	}
+	return pkgs, nil
+}
+
+// appendGlidePackage adds a glide package to the list, merging the subpackages
+// of packages that appear more than once, such as in both imports and test
+// imports.
+func appendGlidePackage(pkgs []download.ManifestPackage, name string, pkg glidePackage) []download.ManifestPackage {
+	for i, existing := range pkgs {
+		if existing.Package != name {
+			continue
+		}
+		for _, subPkg := range pkg.Subpackages {
+			if !download.ContainsString(existing.Subpackages, subPkg) {
+				pkgs[i].Subpackages = append(pkgs[i].Subpackages, subPkg)
+			}
+		}
+		return pkgs
+	}
+	return append(pkgs, download.ManifestPackage{
+		Package:     name,
+		Version:     pkg.Version,
+		Remote:      pkg.Repo,
+		Subpackages: pkg.Subpackages,
+	})
+}
@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// importGodeps reads a godep Godeps/Godeps.json file. Tags found in comments
+// are preferred over revisions.
+func importGodeps(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	var godeps struct {
+		Deps []struct {
+			ImportPath string
+			Rev        string
+			Comment    string
+		}
+	}
+	if err := json.Unmarshal(data, &godeps); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, dep := range godeps.Deps {
+		version := dep.Rev
+		if strings.HasPrefix(dep.Comment, "v") {
+			// Comment looks like a version tag.
+			version = dep.Comment
+		}
+		if pkgs, err = addImportPath(pkgs, logger, dep.ImportPath, version, ""); err != nil {
+			return nil, err
+		}
+	}
+	return pkgs, nil
+}
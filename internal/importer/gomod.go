@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/forked/glideutil"
+)
+
+// goModule is a required module of a go.mod file.
+type goModule struct {
+	path    string
+	version string
+	// remote is set if the module is replaced by another module.
+	remote string
+}
+
+// importGoMod reads a go.mod file. Modules don't list the packages they provide,
+// so subpackages are determined from the imports of the project's Go files.
+func importGoMod(p *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	// go.sum files don't list requirements, read the go.mod next to them.
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(manifest), "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	files, err := p.ProjectImports()
+	if err != nil {
+		return nil, fmt.Errorf("determining project imports: %v", err)
+	}
+	var imports []string
+	for _, fileImports := range files {
+		imports = append(imports, fileImports...)
+	}
+	return parseGoMod(data, imports, logger)
+}
+
+func parseGoMod(data []byte, imports []string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	goMod, err := download.ParseGoMod(data)
+	if err != nil {
+		return nil, err
+	}
+	var modules []goModule
+	for _, req := range goMod.Require {
+		modules = append(modules, goModule{path: req.Path, version: req.Version})
+	}
+	replaces := make(map[string]goModule)
+	for _, r := range goMod.Replace {
+		replaces[r.Old.Path] = goModule{path: r.New.Path, version: r.New.Version}
+	}
+
+	var pkgs []download.ManifestPackage
+	index := make(map[string]int)
+	for _, mod := range modules {
+		if r, ok := replaces[mod.path]; ok {
+			if r.version == "" {
+				logger.Printf("warning: %s is replaced by local directory %s, using the required version", mod.path, r.path)
+			} else {
+				rootPkg, err := glideutil.GetRootFromPackage(r.path)
+				if err != nil {
+					return nil, err
+				}
+				mod.version = r.version
+				if r.path != mod.path {
+					mod.remote = "https://" + rootPkg
+				}
+			}
+		}
+		if goModMajorSuffix.MatchString(mod.path) {
+			logger.Printf("warning: %s uses a major version suffix, which can't be imported from a vendor directory without modules", mod.path)
+		}
+
+		rootPkg, err := glideutil.GetRootFromPackage(mod.path)
+		if err != nil {
+			return nil, err
+		}
+		version := goModRevision(mod.version)
+
+		// Modules of the same repo, such as major versions or nested modules,
+		// are merged into a single package of the repo's root.
+		i, ok := index[rootPkg]
+		if !ok {
+			i = len(pkgs)
+			index[rootPkg] = i
+			pkgs = append(pkgs, download.ManifestPackage{
+				Package: rootPkg,
+				Version: version,
+				Remote:  mod.remote,
+			})
+		} else if pkgs[i].Version != version {
+			logger.Printf("warning: modules of %s are pinned to different versions, using %s instead of %s for %s",
+				rootPkg, displayVersion(pkgs[i].Version), displayVersion(version), mod.path)
+		}
+		pkg := &pkgs[i]
+
+		// Nested modules are kept as a subpackage even when no imports are
+		// found, so their files are vendored.
+		nested := strings.TrimPrefix(strings.TrimPrefix(mod.path, rootPkg), "/")
+		subPkgs := []string{nested}
+		for _, importPath := range imports {
+			if download.InPackage(importPath, mod.path) {
+				subPkgs = append(subPkgs, strings.TrimPrefix(strings.TrimPrefix(importPath, rootPkg), "/"))
+			}
+		}
+		for _, subPkg := range subPkgs {
+			if subPkg != "" && !download.ContainsString(pkg.Subpackages, subPkg) {
+				pkg.Subpackages = append(pkg.Subpackages, subPkg)
+			}
+		}
+		sort.Strings(pkg.Subpackages)
+	}
+	return pkgs, nil
+}
+
+var (
+	goModMajorSuffix   = regexp.MustCompile(`/v[2-9][0-9]*$`)
+	goModPseudoVersion = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+-(?:.*\.)?[0-9]{8,14}-([0-9a-f]{12,40})$`)
+)
+
+// goModRevision converts a module version into a version godl can download.
+// Pseudo-versions, such as "v0.0.0-20170101120000-abcdef123456", become their
+// commit hash, while semantic version tags are kept.
+func goModRevision(version string) string {
+	version = strings.TrimSuffix(version, "+incompatible")
+	if m := goModPseudoVersion.FindStringSubmatch(version); m != nil {
+		return m[1]
+	}
+	return version
+}
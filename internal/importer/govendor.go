@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/forked/glideutil"
+)
+
+// importGovendor reads a govendor vendor/vendor.json file. Each entry is a
+// single package, so entries are grouped by the root package of their repo.
+func importGovendor(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	return parseGovendor(data, logger)
+}
+
+func parseGovendor(data []byte, logger *log.Logger) ([]download.ManifestPackage, error) {
+	var govendor struct {
+		Package []struct {
+			Path         string `json:"path"`
+			Revision     string `json:"revision"`
+			VersionExact string `json:"versionExact"`
+			Origin       string `json:"origin"`
+		} `json:"package"`
+	}
+	if err := json.Unmarshal(data, &govendor); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, dep := range govendor.Package {
+		version := dep.Revision
+		if dep.VersionExact != "" {
+			version = dep.VersionExact
+		}
+
+		remote, err := govendorRemote(dep.Path, dep.Origin, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		if pkgs, err = addImportPath(pkgs, logger, dep.Path, version, remote); err != nil {
+			return nil, err
+		}
+	}
+	return pkgs, nil
+}
+
+// govendorRemote converts the origin of a govendor package, which is the import
+// path the package was fetched from, into a remote.
+func govendorRemote(importPath, origin string, logger *log.Logger) (string, error) {
+	if origin == "" || origin == importPath {
+		return "", nil
+	}
+	if strings.Contains(origin, "/vendor/") {
+		logger.Printf("warning: %s was copied from the vendor directory of %s, using the default remote", importPath, origin)
+		return "", nil
+	}
+	if strings.Contains(origin, "://") {
+		return origin, nil
+	}
+	rootPkg, err := glideutil.GetRootFromPackage(origin)
+	if err != nil {
+		return "", err
+	}
+	return "https://" + rootPkg, nil
+}
@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// importGvt reads a gvt vendor/manifest file. Dependencies are listed by import
+// path and grouped by the root package of their repo.
+func importGvt(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	return parseGvt(data, logger)
+}
+
+func parseGvt(data []byte, logger *log.Logger) ([]download.ManifestPackage, error) {
+	var gvt struct {
+		Dependencies []struct {
+			ImportPath string `json:"importpath"`
+			Repository string `json:"repository"`
+			Revision   string `json:"revision"`
+			Branch     string `json:"branch"`
+			Path       string `json:"path"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &gvt); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	var pkgs []download.ManifestPackage
+	for _, dep := range gvt.Dependencies {
+		importPath := dep.ImportPath
+		if dep.Path != "" && dep.Path != "/" && !strings.HasSuffix(importPath, dep.Path) {
+			// The import path is the repo, path is the vendored directory within it.
+			importPath = strings.TrimSuffix(importPath, "/") + "/" + strings.Trim(dep.Path, "/")
+		}
+
+		version := dep.Revision
+		if version == "" {
+			version = dep.Branch
+		}
+
+		var err error
+		pkgs, err = addImportPath(pkgs, logger, importPath, version, dep.Repository)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Drop remotes that match the default.
+	for i, pkg := range pkgs {
+		if strings.TrimSuffix(pkg.Remote, ".git") == "https://"+pkg.Package {
+			pkgs[i].Remote = ""
+		}
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,205 @@
+// Package importer converts the manifest files of other Go dependency
+// management tools into godl manifest packages.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+	"github.com/ericchiang/godl/internal/forked/glideutil"
+)
+
+// Importer reads the manifest file of another tool.
+type Importer interface {
+	// Name describes the tool and file the importer reads.
+	Name() string
+	// Detect returns the path of a file in the directory that the importer
+	// can read, if one exists.
+	Detect(dir string) (path string, ok bool)
+	// Match reports if the importer reads the file at the path.
+	Match(path string) bool
+	// MatchContent reports if the importer recognizes the contents of a file
+	// whose name doesn't match.
+	MatchContent(data []byte) bool
+	// Import parses the file at the path. The project is used by formats that
+	// inspect source files to determine subpackages.
+	Import(p *download.Project, path string, logger *log.Logger) ([]download.ManifestPackage, error)
+}
+
+// parseFunc parses a manifest file of another tool.
+type parseFunc func(p *download.Project, path string, logger *log.Logger) ([]download.ManifestPackage, error)
+
+// fileImporter is an importer for a file at a fixed location of a project.
+type fileImporter struct {
+	tool string
+	// file is the slash separated path of the file relative to the project.
+	file string
+	// aliases are other file names that should be read by this importer.
+	aliases []string
+	// sniff recognizes the file's contents, if the format can be identified
+	// that way.
+	sniff func(data []byte) bool
+	parse parseFunc
+}
+
+func (f fileImporter) Name() string { return f.tool + " (" + f.file + ")" }
+
+func (f fileImporter) Detect(dir string) (string, bool) {
+	path := filepath.Join(dir, filepath.FromSlash(f.file))
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return path, true
+}
+
+func (f fileImporter) Match(path string) bool {
+	base := filepath.Base(path)
+	if base == pathBase(f.file) {
+		return true
+	}
+	for _, alias := range f.aliases {
+		if base == alias {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fileImporter) MatchContent(data []byte) bool {
+	return f.sniff != nil && f.sniff(data)
+}
+
+func (f fileImporter) Import(p *download.Project, path string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	return f.parse(p, path, logger)
+}
+
+func pathBase(slashPath string) string {
+	return slashPath[strings.LastIndex(slashPath, "/")+1:]
+}
+
+// hasJSONKey returns a function reporting if data is a JSON object with the
+// key.
+func hasJSONKey(key string) func(data []byte) bool {
+	return func(data []byte) bool {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return false
+		}
+		_, ok := obj[key]
+		return ok
+	}
+}
+
+// Importers holds every supported format, ordered from most to least precise.
+// Formats that pin each package to an exact revision come first, followed by
+// formats that may only name tags, then manifests that allow version ranges.
+var Importers = []Importer{
+	fileImporter{tool: "godep", file: "Godeps/Godeps.json", sniff: hasJSONKey("Deps"), parse: importGodeps},
+	fileImporter{tool: "glide", file: "glide.lock", parse: importGlide},
+	fileImporter{tool: "dep", file: "Gopkg.lock", parse: importDep},
+	fileImporter{tool: "govendor", file: "vendor/vendor.json", sniff: hasJSONKey("package"), parse: importGovendor},
+	fileImporter{tool: "gvt", file: "vendor/manifest", sniff: hasJSONKey("dependencies"), parse: importGvt},
+	fileImporter{tool: "Go modules", file: "go.mod", aliases: []string{"go.sum"}, parse: importGoMod},
+	fileImporter{tool: "vndr", file: "vendor.conf", parse: importVendorConf},
+	fileImporter{tool: "glide", file: "glide.yaml", parse: importGlide},
+	fileImporter{tool: "dep", file: "Gopkg.toml", parse: importDep},
+}
+
+// ForFile returns the importer that reads the file at the path. Files are
+// matched by name, then by their contents, so a file such as a Godeps.json
+// saved under another name can still be imported.
+func ForFile(path string) (Importer, error) {
+	for _, i := range Importers {
+		if i.Match(path) {
+			return i, nil
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	for _, i := range Importers {
+		if i.MatchContent(data) {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported manifest file %s, supported files are %s", path, supportedFiles())
+}
+
+// Detect looks for files of every supported format in a directory and returns
+// the most precise importer found, along with the path of its file.
+func Detect(dir string) (Importer, string, error) {
+	for _, i := range Importers {
+		if path, ok := i.Detect(dir); ok {
+			return i, path, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no manifest files found in %s, supported files are %s", dir, supportedFiles())
+}
+
+func supportedFiles() string {
+	var files []string
+	for _, i := range Importers {
+		if f, ok := i.(fileImporter); ok {
+			files = append(files, f.file)
+		}
+	}
+	return strings.Join(files, ", ")
+}
+
+// addImportPath adds a dependency listed by import path, which may be a
+// subpackage, to a list of root packages. If the root package is already
+// present, the import path is added to its subpackages and the existing
+// version is kept, logging a warning if the versions differ.
+func addImportPath(pkgs []download.ManifestPackage, logger *log.Logger, importPath, version, remote string) ([]download.ManifestPackage, error) {
+	rootPkg, err := glideutil.GetRootFromPackage(importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	subPkg := strings.TrimPrefix(strings.TrimPrefix(importPath, rootPkg), "/")
+
+	for i, pkg := range pkgs {
+		if pkg.Package != rootPkg {
+			continue
+		}
+		if pkg.Version != version {
+			logger.Printf("warning: packages of %s are pinned to different versions, using %s instead of %s for %s",
+				rootPkg, displayVersion(pkg.Version), displayVersion(version), importPath)
+		}
+		if subPkg != "" && !download.ContainsString(pkg.Subpackages, subPkg) {
+			pkgs[i].Subpackages = append(pkg.Subpackages, subPkg)
+		}
+		return pkgs, nil
+	}
+
+	pkg := download.ManifestPackage{
+		Package: rootPkg,
+		Version: version,
+		Remote:  remote,
+	}
+	if subPkg != "" {
+		pkg.Subpackages = []string{subPkg}
+	}
+	return append(pkgs, pkg), nil
+}
+
+// isVersionRange reports if a version uses range operators, such as "^1.2.0"
+// or ">= 1.0, < 2.0", rather than naming an exact version.
+func isVersionRange(version string) bool {
+	return strings.ContainsAny(version, "^~<>*|, ") || strings.Contains(version, ".x")
+}
+
+func displayVersion(v string) string {
+	if v == "" {
+		return "latest"
+	}
+	return v
+}
@@ -0,0 +1,451 @@
+package importer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+var discard = log.New(ioutil.Discard, "", 0)
+
+func TestParseGlide(t *testing.T) {
+	manifest := `
+package: github.com/me/project
+import:
+- package: github.com/foo/bar
+  version: ^1.2.0
+  subpackages:
+  - baz
+- package: github.com/foo/private
+  version: v2.0.0
+  repo: git@github.com:foo/private.git
+testImport:
+- package: github.com/foo/bar
+  subpackages:
+  - testutil
+`
+	lock := `
+hash: 6a2b5e0b9d2d9a3b30e2b6e5c0e8b2a6b0a0d0c7a1b3d9e0a1b2c3d4e5f6a7b8
+updated: 2017-06-01T10:00:00.000000000-07:00
+imports:
+- name: github.com/foo/bar
+  version: 3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f
+  subpackages:
+  - baz
+- name: github.com/foo/private
+  version: 9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e
+  repo: git@github.com:foo/private.git
+testImports:
+- name: github.com/foo/bar
+  version: 3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f
+  subpackages:
+  - testutil
+`
+	got, err := parseGlideYAML([]byte(manifest), discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{Package: "github.com/foo/bar", Subpackages: []string{"baz", "testutil"}},
+		{Package: "github.com/foo/private", Version: "v2.0.0", Remote: "git@github.com:foo/private.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsing glide.yaml, expected %#v got %#v", want, got)
+	}
+
+	got, err = parseGlideLock([]byte(lock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []download.ManifestPackage{
+		{
+			Package:     "github.com/foo/bar",
+			Version:     "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			Subpackages: []string{"baz", "testutil"},
+		},
+		{
+			Package: "github.com/foo/private",
+			Version: "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e",
+			Remote:  "git@github.com:foo/private.git",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsing glide.lock, expected %#v got %#v", want, got)
+	}
+}
+
+func TestParseGvt(t *testing.T) {
+	manifest := `{
+	"version": 0,
+	"dependencies": [
+		{
+			"importpath": "github.com/foo/bar/baz",
+			"repository": "https://github.com/foo/bar",
+			"vcs": "git",
+			"revision": "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			"branch": "master",
+			"path": "/baz"
+		},
+		{
+			"importpath": "github.com/foo/bar/qux",
+			"repository": "https://github.com/foo/bar",
+			"vcs": "git",
+			"revision": "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			"branch": "master",
+			"path": "/qux"
+		},
+		{
+			"importpath": "github.com/foo/private",
+			"repository": "git@github.com:foo/private.git",
+			"vcs": "git",
+			"revision": "",
+			"branch": "release",
+			"path": ""
+		}
+	]
+}`
+	got, err := parseGvt([]byte(manifest), discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{
+			Package:     "github.com/foo/bar",
+			Version:     "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			Subpackages: []string{"baz", "qux"},
+		},
+		{
+			Package: "github.com/foo/private",
+			Version: "release",
+			Remote:  "git@github.com:foo/private.git",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v got %#v", want, got)
+	}
+}
+
+func TestParseDep(t *testing.T) {
+	manifest := `
+required = ["github.com/foo/tool"]
+
+[[constraint]]
+  name = "github.com/foo/bar"
+  version = "^1.2.0"
+
+[[constraint]]
+  name = "github.com/foo/private"
+  source = "git@github.com:foo/private.git"
+  branch = "release"
+
+[[override]]
+  name = "github.com/foo/transitive"
+  revision = "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e"
+`
+	lock := `
+[[projects]]
+  name = "github.com/foo/bar"
+  packages = [".", "baz"]
+  revision = "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f"
+  version = "v1.2.3"
+
+[[projects]]
+  branch = "release"
+  name = "github.com/foo/private"
+  packages = ["."]
+  revision = "5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b"
+  source = "git@github.com:foo/private.git"
+
+[solve-meta]
+  analyzer-name = "dep"
+  analyzer-version = 1
+  inputs-digest = "0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e"
+  solver-name = "gps-cdcl"
+  solver-version = 1
+`
+	got, err := parseDep([]byte(manifest), []byte(lock), discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{Package: "github.com/foo/bar", Version: "v1.2.3", Subpackages: []string{"baz"}},
+		{
+			Package: "github.com/foo/private",
+			Version: "5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+			Remote:  "git@github.com:foo/private.git",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsing Gopkg.lock, expected %#v got %#v", want, got)
+	}
+
+	got, err = parseDep([]byte(manifest), nil, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []download.ManifestPackage{
+		{Package: "github.com/foo/transitive", Version: "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e"},
+		{Package: "github.com/foo/bar"},
+		{Package: "github.com/foo/private", Version: "release", Remote: "git@github.com:foo/private.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsing Gopkg.toml, expected %#v got %#v", want, got)
+	}
+}
+
+func TestParseGovendor(t *testing.T) {
+	manifest := `{
+	"comment": "",
+	"ignore": "test",
+	"package": [
+		{
+			"checksumSHA1": "Zy7nYvT8Q2s2Qq3Q0DgCMD1JdMg=",
+			"path": "github.com/foo/bar",
+			"revision": "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			"revisionTime": "2017-05-01T10:00:00Z"
+		},
+		{
+			"checksumSHA1": "q2Ls5rBCQZyB9sn+Qx3tG5H7Xko=",
+			"path": "github.com/foo/bar/baz",
+			"revision": "0f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6",
+			"revisionTime": "2017-06-01T10:00:00Z"
+		},
+		{
+			"checksumSHA1": "3b5yv7tqX1b8bV1l0VvO2i4lJ0w=",
+			"origin": "github.com/fork/qux/sub",
+			"path": "github.com/foo/qux/sub",
+			"revision": "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e",
+			"revisionTime": "2017-04-01T10:00:00Z",
+			"version": "v1",
+			"versionExact": "v1.0.2"
+		}
+	],
+	"rootPath": "github.com/me/project"
+}`
+	var buf bytes.Buffer
+	got, err := parseGovendor([]byte(manifest), log.New(&buf, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{
+			Package:     "github.com/foo/bar",
+			Version:     "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			Subpackages: []string{"baz"},
+		},
+		{
+			Package:     "github.com/foo/qux",
+			Version:     "v1.0.2",
+			Remote:      "https://github.com/fork/qux",
+			Subpackages: []string{"sub"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v got %#v", want, got)
+	}
+	if !strings.Contains(buf.String(), "pinned to different versions") {
+		t.Errorf("expected a warning about conflicting revisions, got %q", buf.String())
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	manifest := `module github.com/me/project
+
+go 1.12
+
+require (
+	github.com/foo/bar v1.2.3
+	github.com/foo/pseudo v0.0.0-20170101120000-abcdef123456 // indirect
+	github.com/foo/prerelease v1.2.4-0.20191109021931-daa7c04131f5
+	github.com/foo/incompatible v2.0.0+incompatible
+	github.com/foo/forked v1.0.0
+	github.com/foo/local v1.0.0
+	github.com/foo/bar/v2 v2.1.0
+	github.com/foo/bar/sub v1.2.3
+)
+
+require github.com/foo/single v0.1.0
+
+replace github.com/foo/forked => github.com/fork/forked v1.0.1
+
+replace (
+	github.com/foo/local => ../local
+)
+`
+	imports := []string{
+		"fmt",
+		"github.com/foo/bar/baz",
+		"github.com/foo/bar",
+		"github.com/foo/bar/qux",
+		"github.com/foo/bar/baz",
+		"github.com/foo/bar/v2/quux",
+		"github.com/me/project/internal",
+	}
+	var buf bytes.Buffer
+	got, err := parseGoMod([]byte(manifest), imports, log.New(&buf, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{Package: "github.com/foo/bar", Version: "v1.2.3", Subpackages: []string{"baz", "qux", "sub", "v2", "v2/quux"}},
+		{Package: "github.com/foo/pseudo", Version: "abcdef123456"},
+		{Package: "github.com/foo/prerelease", Version: "daa7c04131f5"},
+		{Package: "github.com/foo/incompatible", Version: "v2.0.0"},
+		{Package: "github.com/foo/forked", Version: "v1.0.1", Remote: "https://github.com/fork/forked"},
+		{Package: "github.com/foo/local", Version: "v1.0.0"},
+		{Package: "github.com/foo/single", Version: "v0.1.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v got %#v", want, got)
+	}
+	if !strings.Contains(buf.String(), "github.com/foo/bar are pinned to different versions") {
+		t.Errorf("expected a warning about conflicting versions, got %q", buf.String())
+	}
+}
+
+func TestParseVendorConf(t *testing.T) {
+	manifest := `# Dependencies of the project.
+github.com/foo/bar v1.2.3
+github.com/foo/private   3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f   https://alt/remote.git # fork
+
+	# Indented comment.
+github.com/foo/latest
+`
+	got, err := parseVendorConf([]byte(manifest), discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []download.ManifestPackage{
+		{Package: "github.com/foo/bar", Version: "v1.2.3"},
+		{
+			Package: "github.com/foo/private",
+			Version: "3a4e5f9e1f1e8f0f5a1b2c3d4e5f6a7b8c9d0e1f",
+			Remote:  "https://alt/remote.git",
+		},
+		{Package: "github.com/foo/latest"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v got %#v", want, got)
+	}
+
+	if _, err := parseVendorConf([]byte("github.com/foo/bar v1 remote extra\n"), discard); err == nil {
+		t.Errorf("expected error for line with too many fields")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, _, err := Detect(dir); err == nil {
+		t.Errorf("expected error detecting manifest in empty directory")
+	}
+
+	// Files are added from least to most precise.
+	files := []struct {
+		path string
+		want string
+	}{
+		{"Gopkg.toml", "dep (Gopkg.toml)"},
+		{"glide.yaml", "glide (glide.yaml)"},
+		{"go.mod", "Go modules (go.mod)"},
+		{"vendor/vendor.json", "govendor (vendor/vendor.json)"},
+		{"Godeps/Godeps.json", "godep (Godeps/Godeps.json)"},
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		i, got, err := Detect(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.Name() != file.want || got != path {
+			t.Errorf("after adding %s, expected to detect %s, got %s at %s", file.path, file.want, i.Name(), got)
+		}
+	}
+}
+
+func TestForFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Godeps/Godeps.json", "godep (Godeps/Godeps.json)"},
+		{"../other/glide.yaml", "glide (glide.yaml)"},
+		{"go.sum", "Go modules (go.mod)"},
+		{"vendor/manifest", "gvt (vendor/manifest)"},
+		{"requirements.txt", ""},
+	}
+	for _, test := range tests {
+		i, err := ForFile(test.path)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("ForFile(%s): expected error", test.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ForFile(%s): %v", test.path, err)
+			continue
+		}
+		if i.Name() != test.want {
+			t.Errorf("ForFile(%s), want=%s, got=%s", test.path, test.want, i.Name())
+		}
+	}
+}
+
+func TestForFileContents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"deps.json", `{"ImportPath": "github.com/me/project", "Deps": []}`, "godep (Godeps/Godeps.json)"},
+		{"vendor-lock.json", `{"package": []}`, "govendor (vendor/vendor.json)"},
+		{"gvt.json", `{"version": 0, "dependencies": []}`, "gvt (vendor/manifest)"},
+		{"other.json", `{"foo": "bar"}`, ""},
+		{"deps.txt", `github.com/foo/bar v1.2.3`, ""},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		if err := ioutil.WriteFile(path, []byte(test.contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		i, err := ForFile(path)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("ForFile(%s): expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ForFile(%s): %v", test.name, err)
+			continue
+		}
+		if i.Name() != test.want {
+			t.Errorf("ForFile(%s), want=%s, got=%s", test.name, test.want, i.Name())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// importVendorConf reads a vendor.conf file used by vndr and trash. Each line
+// holds a package, its version, and an optional remote separated by whitespace.
+// Text after a '#' is a comment.
+func importVendorConf(_ *download.Project, manifest string, logger *log.Logger) ([]download.ManifestPackage, error) {
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	return parseVendorConf(data, logger)
+}
+
+func parseVendorConf(data []byte, logger *log.Logger) ([]download.ManifestPackage, error) {
+	var pkgs []download.ManifestPackage
+	for i, line := range strings.Split(string(data), "\n") {
+		if j := strings.Index(line, "#"); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("vendor.conf:%d: expected package, version and optional remote, got %q", i+1, strings.TrimSpace(line))
+		}
+
+		var version, remote string
+		if len(fields) > 1 {
+			version = fields[1]
+		}
+		if len(fields) > 2 {
+			remote = fields[2]
+		}
+
+		var err error
+		if pkgs, err = addImportPath(pkgs, logger, fields[0], version, remote); err != nil {
+			return nil, err
+		}
+	}
+	return pkgs, nil
+}
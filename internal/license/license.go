@@ -0,0 +1,175 @@
+// Package license identifies licenses by comparing them against bundled
+// license texts.
+package license
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// threshold is the minimum similarity for a text to match a license.
+	threshold = 0.75
+	// coverage is the fraction of a license that must appear in a text for
+	// the text to include that license.
+	coverage = 0.9
+	// minNotice is the minimum number of word triples of a notice that refers
+	// to a license, such as the header recommended by the Apache license.
+	minNotice = 10
+)
+
+var (
+	templatesOnce sync.Once
+	templates     map[string]map[string]bool
+)
+
+// Classify returns the SPDX identifiers of the bundled licenses found in the
+// text, sorted. Texts are compared as sets of consecutive word triples,
+// ignoring case and punctuation, so copyright lines and formatting have little
+// effect.
+//
+// A text similar to a single license matches that license. Otherwise, every
+// license almost entirely contained in the text matches, which handles files
+// that concatenate several licenses. Finally, short notices copied from a
+// license, such as "Licensed under the Apache License, Version 2.0", match the
+// license they're part of.
+func Classify(text string) []string {
+	templatesOnce.Do(func() {
+		templates = make(map[string]map[string]bool)
+		for id, t := range texts {
+			templates[id] = shingles(t)
+		}
+	})
+
+	s := shingles(text)
+	best, bestScore := "", 0.0
+	for _, id := range IDs() {
+		if score := similarity(s, templates[id]); score > bestScore {
+			best, bestScore = id, score
+		}
+	}
+	if bestScore >= threshold {
+		return []string{best}
+	}
+
+	var found []string
+	for _, id := range IDs() {
+		if contained(templates[id], s) >= coverage {
+			found = append(found, id)
+		}
+	}
+	if len(found) > 0 {
+		return dropSubsumed(found)
+	}
+
+	if len(s) < minNotice {
+		return nil
+	}
+	best, bestScore = "", 0.0
+	for _, id := range IDs() {
+		if score := contained(s, templates[id]); score > bestScore {
+			best, bestScore = id, score
+		}
+	}
+	if bestScore >= threshold {
+		return []string{best}
+	}
+	return nil
+}
+
+// dropSubsumed removes licenses whose text is contained in another license of
+// the list, such as BSD-2-Clause within BSD-3-Clause.
+func dropSubsumed(ids []string) []string {
+	var kept []string
+	for _, id := range ids {
+		subsumed := false
+		for _, other := range ids {
+			if other != id && contained(templates[id], templates[other]) >= coverage {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// IDs returns the SPDX identifiers of the bundled licenses, sorted.
+func IDs() []string {
+	var ids []string
+	for id := range texts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// deprecated maps deprecated SPDX identifiers to their current equivalents.
+var deprecated = map[string]string{
+	"GPL-2.0":  "GPL-2.0-only",
+	"GPL-3.0":  "GPL-3.0-only",
+	"LGPL-2.1": "LGPL-2.1-only",
+	"LGPL-3.0": "LGPL-3.0-only",
+}
+
+// Normalize returns the current SPDX identifier of a deprecated one, such as
+// "GPL-3.0-only" for "GPL-3.0". Other identifiers are returned unchanged.
+func Normalize(id string) string {
+	if current, ok := deprecated[id]; ok {
+		return current
+	}
+	return id
+}
+
+// Same reports if two SPDX identifiers refer to the same license text. Since
+// Classify can't tell whether later versions of a GNU license may be used, the
+// "-only" and "-or-later" variants of a license are the same.
+func Same(a, b string) bool {
+	text := func(id string) string {
+		id = Normalize(id)
+		return strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later")
+	}
+	return text(a) == text(b)
+}
+
+// shingles returns the set of consecutive word triples of a text.
+func shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool)
+	for i := 0; i+3 <= len(words); i++ {
+		set[strings.Join(words[i:i+3], " ")] = true
+	}
+	return set
+}
+
+// similarity computes the Dice coefficient of two sets.
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return 2 * float64(intersection(a, b)) / float64(len(a)+len(b))
+}
+
+// contained returns the fraction of a that is also in b.
+func contained(a, b map[string]bool) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	return float64(intersection(a, b)) / float64(len(a))
+}
+
+func intersection(a, b map[string]bool) int {
+	n := 0
+	for s := range a {
+		if b[s] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,58 @@
+package license
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	for _, id := range IDs() {
+		if got := Classify(texts[id]); !reflect.DeepEqual(got, []string{id}) {
+			t.Errorf("classifying text of %s, got %q", id, got)
+		}
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "copyright line",
+			text: "Copyright (c) 2017 Jane Doe\n\n" + mitText,
+			want: []string{"MIT"},
+		},
+		{
+			name: "multiple licenses",
+			text: mitText + "\n\nPortions of this package are derived from:\n\n" + bsd3Text,
+			want: []string{"BSD-3-Clause", "MIT"},
+		},
+		{
+			name: "apache notice",
+			text: `Copyright 2011-2016 Canonical Ltd.
+
+			Licensed under the Apache License, Version 2.0 (the "License");
+			you may not use this file except in compliance with the License.
+			You may obtain a copy of the License at
+
+			    http://www.apache.org/licenses/LICENSE-2.0
+
+			Unless required by applicable law or agreed to in writing, software
+			distributed under the License is distributed on an "AS IS" BASIS,
+			WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+			See the License for the specific language governing permissions and
+			limitations under the License.
+			`,
+			want: []string{"Apache-2.0"},
+		},
+		{
+			name: "unknown",
+			text: "You may do anything you want with this code, except blame me.",
+		},
+	}
+	for _, test := range tests {
+		if got := Classify(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %q got %q", test.name, test.want, got)
+		}
+	}
+}
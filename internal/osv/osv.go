@@ -0,0 +1,155 @@
+// Package osv reads vulnerability advisories in the Open Source Vulnerability
+// (OSV) format, such as those published by the Go vulnerability database.
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Entry is a single advisory.
+type Entry struct {
+	ID        string     `json:"id"`
+	Withdrawn string     `json:"withdrawn,omitempty"`
+	Aliases   []string   `json:"aliases,omitempty"`
+	Summary   string     `json:"summary,omitempty"`
+	Details   string     `json:"details,omitempty"`
+	Affected  []Affected `json:"affected"`
+}
+
+// Affected describes the versions of a package an advisory applies to.
+type Affected struct {
+	Package  Package  `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+
+	EcosystemSpecific EcosystemSpecific `json:"ecosystem_specific"`
+}
+
+// EcosystemSpecific holds the Go specific details of an affected package.
+type EcosystemSpecific struct {
+	// Imports are the affected packages of a Go module, along with their
+	// affected symbols.
+	Imports []Import `json:"imports,omitempty"`
+}
+
+// Package identifies a package within an ecosystem.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Range types.
+const (
+	RangeSemver    = "SEMVER"
+	RangeEcosystem = "ECOSYSTEM"
+	RangeGit       = "GIT"
+)
+
+// Range is a sequence of events that introduce and fix a vulnerability. The
+// events of SEMVER and ECOSYSTEM ranges are versions, while those of GIT
+// ranges are commits.
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event is a version or commit where a package's vulnerability status changes.
+// Exactly one field is set. An introduced version of "0" means all versions.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Import is an affected package of a Go module.
+type Import struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// goEcosystem is the ecosystem of Go packages.
+const goEcosystem = "Go"
+
+// Load reads every advisory under a directory. Files may hold a single entry
+// or a list of entries. Other JSON files, such as the indexes of the Go
+// vulnerability database, are skipped, as are withdrawn advisories and
+// packages outside the Go ecosystem. Entries are sorted by ID.
+func Load(dir string) ([]Entry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		found, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("parse %s: %v", path, err)
+		}
+		entries = append(entries, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+// parse decodes the advisories of a file.
+func parse(data []byte) ([]Entry, error) {
+	var candidates []Entry
+	if s := strings.TrimSpace(string(data)); strings.HasPrefix(s, "[") {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		for _, r := range raw {
+			var e Entry
+			if err := json.Unmarshal(r, &e); err != nil {
+				// Not an advisory, such as an index entry.
+				continue
+			}
+			candidates = append(candidates, e)
+		}
+	} else {
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, e)
+	}
+
+	var entries []Entry
+	for _, e := range candidates {
+		if e.ID == "" || e.Withdrawn != "" {
+			continue
+		}
+		var affected []Affected
+		for _, a := range e.Affected {
+			if a.Package.Ecosystem == goEcosystem {
+				affected = append(affected, a)
+			}
+		}
+		if len(affected) == 0 {
+			continue
+		}
+		e.Affected = affected
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
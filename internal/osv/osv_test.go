@@ -0,0 +1,54 @@
+package osv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "godl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"ID/GO-2022-0002.json": `{"id":"GO-2022-0002","affected":[{"package":{"ecosystem":"Go","name":"github.com/foo/bar"}}]}`,
+		"ID/GO-2022-0001.json": `{"id":"GO-2022-0001","affected":[
+			{"package":{"ecosystem":"Go","name":"github.com/foo/baz"}},
+			{"package":{"ecosystem":"npm","name":"baz"}}
+		]}`,
+		"ID/GO-2022-0003.json": `{"id":"GO-2022-0003","withdrawn":"2022-01-01T00:00:00Z","affected":[{"package":{"ecosystem":"Go","name":"github.com/foo/bar"}}]}`,
+		"ID/PYSEC-1.json":      `{"id":"PYSEC-1","affected":[{"package":{"ecosystem":"PyPI","name":"foo"}}]}`,
+		"index/modules.json":   `[{"path":"github.com/foo/bar","vulns":[{"id":"GO-2022-0002"}]}]`,
+		"index/db.json":        `{"modified":"2022-01-01T00:00:00Z"}`,
+		"README.md":            `not an advisory`,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		for _, a := range e.Affected {
+			got = append(got, e.ID+" "+a.Package.Name)
+		}
+	}
+	want := []string{"GO-2022-0001 github.com/foo/baz", "GO-2022-0002 github.com/foo/bar"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want=%q, got=%q", want, got)
+	}
+}
@@ -0,0 +1,243 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// cdxSpecVersion is the version of the CycloneDX specification BOMs follow.
+const cdxSpecVersion = "1.5"
+
+// cdxBOM is a CycloneDX BOM. Fields are tagged for both the JSON and XML
+// encodings. Where the encodings differ in structure, separate fields hold
+// each one.
+type cdxBOM struct {
+	XMLName      xml.Name        `json:"-" xml:"bom"`
+	XMLNS        string          `json:"-" xml:"xmlns,attr"`
+	BOMFormat    string          `json:"bomFormat" xml:"-"`
+	SpecVersion  string          `json:"specVersion" xml:"-"`
+	SerialNumber string          `json:"serialNumber" xml:"serialNumber,attr"`
+	Version      int             `json:"version" xml:"version,attr"`
+	Metadata     cdxMetadata     `json:"metadata" xml:"metadata"`
+	Components   cdxComponents   `json:"components" xml:"components"`
+	Dependencies []cdxDependency `json:"dependencies" xml:"dependencies>dependency"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp" xml:"timestamp"`
+	Tools     []cdxTool    `json:"tools" xml:"tools>tool"`
+	Component cdxComponent `json:"component" xml:"component"`
+}
+
+type cdxTool struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type cdxComponent struct {
+	Type               string                `json:"type" xml:"type,attr"`
+	BOMRef             string                `json:"bom-ref" xml:"bom-ref,attr"`
+	Name               string                `json:"name" xml:"name"`
+	Version            string                `json:"version,omitempty" xml:"version,omitempty"`
+	Hashes             cdxHashes             `json:"hashes,omitempty" xml:"hashes"`
+	Licenses           []cdxLicenseChoice    `json:"licenses,omitempty" xml:"-"`
+	XMLLicenses        cdxLicenses           `json:"-" xml:"licenses"`
+	PURL               string                `json:"purl,omitempty" xml:"purl,omitempty"`
+	ExternalReferences cdxExternalReferences `json:"externalReferences,omitempty" xml:"externalReferences"`
+	Components         cdxComponents         `json:"components,omitempty" xml:"components"`
+}
+
+// List types are encoded as XML by encodeList, since encoding/xml writes empty
+// parent elements for "parent>child" tags of empty lists.
+type (
+	cdxHashes             []cdxHash
+	cdxLicenses           []cdxLicense
+	cdxExternalReferences []cdxExternalReference
+	cdxComponents         []cdxComponent
+)
+
+func (l cdxHashes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeList(e, start, "hash", []cdxHash(l), len(l))
+}
+
+func (l cdxLicenses) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeList(e, start, "license", []cdxLicense(l), len(l))
+}
+
+func (l cdxExternalReferences) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeList(e, start, "reference", []cdxExternalReference(l), len(l))
+}
+
+func (l cdxComponents) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeList(e, start, "component", []cdxComponent(l), len(l))
+}
+
+// encodeList encodes each item of a list as a child of the start element. If
+// the list is empty, nothing is written.
+func encodeList(e *xml.Encoder, start xml.StartElement, child string, list interface{}, n int) error {
+	if n == 0 {
+		return nil
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.EncodeElement(list, xml.StartElement{Name: xml.Name{Local: child}}); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg" xml:"alg,attr"`
+	Content string `json:"content" xml:",chardata"`
+}
+
+type cdxLicenseChoice struct {
+	License cdxLicense `json:"license"`
+}
+
+type cdxLicense struct {
+	ID string `json:"id" xml:"id"`
+}
+
+type cdxExternalReference struct {
+	Type string `json:"type" xml:"type,attr"`
+	URL  string `json:"url" xml:"url"`
+}
+
+type cdxDependency struct {
+	Ref          string          `json:"ref" xml:"ref,attr"`
+	DependsOn    []string        `json:"dependsOn,omitempty" xml:"-"`
+	XMLDependsOn []cdxDependency `json:"-" xml:"dependency"`
+}
+
+// WriteCycloneDX writes a CycloneDX BOM as JSON, or XML if asXML is set. Each
+// package of the lock file is a component, with its vendored subpackages as
+// nested components. Dependencies record which packages import which others.
+func WriteCycloneDX(w io.Writer, b *BOM, asXML bool) error {
+	serial, err := newUUID()
+	if err != nil {
+		return err
+	}
+	bom := cdxBOM{
+		XMLNS:        "http://cyclonedx.org/schema/bom/" + cdxSpecVersion,
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cdxSpecVersion,
+		SerialNumber: "urn:uuid:" + serial,
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: b.Created.UTC().Format(time.RFC3339),
+			Tools:     []cdxTool{{Name: "godl"}},
+			Component: cdxComponent{Type: "application", BOMRef: b.Project, Name: b.Project},
+		},
+		Components: cdxComponents{},
+	}
+
+	// refs maps the import paths of vendored packages to their component.
+	refs := make(map[string]string)
+	for _, pkg := range b.Packages {
+		c := cdxComponent{
+			Type:    "library",
+			Name:    pkg.Package,
+			Version: pkg.Version,
+			PURL:    purl(pkg.Package, pkg.Rev(), ""),
+			Hashes:  cdxHashes{{"SHA-256", download.HashHex(pkg.TreeHash)}},
+			ExternalReferences: cdxExternalReferences{
+				{Type: "vcs", URL: download.RemoteOf(download.ManifestPackage{Package: pkg.Package, Remote: pkg.Remote})},
+			},
+		}
+		c.BOMRef = c.PURL
+		refs[pkg.Package] = c.BOMRef
+		for _, id := range download.LicenseIDs(pkg.Licenses) {
+			c.Licenses = append(c.Licenses, cdxLicenseChoice{cdxLicense{id}})
+			c.XMLLicenses = append(c.XMLLicenses, cdxLicense{id})
+		}
+
+		for _, importPath := range sortedKeys(b.Imports) {
+			if importPath == pkg.Package || !download.InPackage(importPath, pkg.Package) {
+				continue
+			}
+			subPkg := strings.TrimPrefix(importPath, pkg.Package+"/")
+			sub := cdxComponent{
+				Type:    "library",
+				Name:    importPath,
+				Version: pkg.Version,
+				PURL:    purl(pkg.Package, pkg.Rev(), subPkg),
+			}
+			sub.BOMRef = sub.PURL
+			refs[importPath] = sub.BOMRef
+			c.Components = append(c.Components, sub)
+		}
+		bom.Components = append(bom.Components, c)
+	}
+
+	addDependency := func(ref string, imports []string) {
+		d := cdxDependency{Ref: ref}
+		for _, importPath := range imports {
+			if dep, ok := refs[importPath]; ok {
+				d.DependsOn = append(d.DependsOn, dep)
+				d.XMLDependsOn = append(d.XMLDependsOn, cdxDependency{Ref: dep})
+			}
+		}
+		bom.Dependencies = append(bom.Dependencies, d)
+	}
+	addDependency(b.Project, b.ProjectImports)
+	for _, importPath := range sortedKeys(b.Imports) {
+		if ref, ok := refs[importPath]; ok {
+			addDependency(ref, b.Imports[importPath])
+		}
+	}
+
+	if asXML {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		e := xml.NewEncoder(w)
+		e.Indent("", "  ")
+		if err := e.Encode(bom); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(bom)
+}
+
+// purl returns the package URL of a Go package at a revision. Subpackages are
+// identified by the subpath of their repo's root package.
+func purl(rootPkg, rev, subPkg string) string {
+	s := "pkg:golang/" + rootPkg + "@" + rev
+	if subPkg != "" {
+		s += "#" + subPkg
+	}
+	return s
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func sortedKeys(g download.ImportGraph) []string {
+	var keys []string
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
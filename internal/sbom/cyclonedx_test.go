@@ -0,0 +1,98 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+func testBOM() *BOM {
+	return &BOM{
+		Project: "project",
+		Created: time.Unix(0, 0),
+		Packages: []Package{
+			{
+				LockPackage: download.LockPackage{Package: "github.com/a/b", Version: "v1.0.0", Revision: "abc"},
+				TreeHash:    "sha256:01",
+			},
+			{
+				LockPackage: download.LockPackage{Package: "github.com/d/e", Version: "def"},
+				TreeHash:    "sha256:02",
+			},
+		},
+		Imports: download.ImportGraph{
+			"github.com/a/b":   {"github.com/a/b/c"},
+			"github.com/a/b/c": {"github.com/d/e"},
+			"github.com/d/e":   nil,
+		},
+		ProjectImports: []string{"github.com/a/b"},
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteCycloneDX(buf, testBOM(), false); err != nil {
+		t.Fatal(err)
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bom.Components) != 2 || len(bom.Components[0].Components) != 1 {
+		t.Fatalf("expected 2 components with 1 subpackage, got %#v", bom.Components)
+	}
+	if got, want := bom.Components[0].Components[0].PURL, "pkg:golang/github.com/a/b@abc#c"; got != want {
+		t.Errorf("expected subpackage purl %s, got %s", want, got)
+	}
+
+	got := make(map[string][]string)
+	for _, d := range bom.Dependencies {
+		got[d.Ref] = d.DependsOn
+	}
+	want := map[string][]string{
+		"project":                         {"pkg:golang/github.com/a/b@abc"},
+		"pkg:golang/github.com/a/b@abc":   {"pkg:golang/github.com/a/b@abc#c"},
+		"pkg:golang/github.com/a/b@abc#c": {"pkg:golang/github.com/d/e@def"},
+		"pkg:golang/github.com/d/e@def":   nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected dependencies %q got %q", want, got)
+	}
+}
+
+func TestWriteCycloneDXXML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteCycloneDX(buf, testBOM(), true); err != nil {
+		t.Fatal(err)
+	}
+	var bom struct {
+		Components []struct {
+			PURL   string `xml:"purl"`
+			Hashes []struct {
+				Alg     string `xml:"alg,attr"`
+				Content string `xml:",chardata"`
+			} `xml:"hashes>hash"`
+		} `xml:"components>component"`
+		Dependencies []struct {
+			Ref       string `xml:"ref,attr"`
+			DependsOn []struct {
+				Ref string `xml:"ref,attr"`
+			} `xml:"dependency"`
+		} `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatal(err)
+	}
+	if len(bom.Components) != 2 || len(bom.Components[1].Hashes) != 1 || bom.Components[1].Hashes[0].Content != "02" {
+		t.Errorf("unexpected components %#v", bom.Components)
+	}
+	if len(bom.Dependencies) != 4 || bom.Dependencies[0].DependsOn[0].Ref != "pkg:golang/github.com/a/b@abc" {
+		t.Errorf("unexpected dependencies %#v", bom.Dependencies)
+	}
+}
@@ -0,0 +1,139 @@
+// Package sbom generates software bills of materials describing the vendored
+// dependencies of a project.
+package sbom
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// BOM describes a project and its vendored dependencies.
+type BOM struct {
+	// Project is the name of the project.
+	Project string
+	// Created is when the BOM was generated.
+	Created time.Time
+	// Packages holds each package of the lock file.
+	Packages []Package
+
+	// Imports holds the vendored packages each vendored package imports.
+	Imports download.ImportGraph
+	// ProjectImports are the vendored packages imported directly by the
+	// project's own Go files, sorted.
+	ProjectImports []string
+}
+
+// Package is a locked package along with what's known about its vendored files.
+type Package struct {
+	download.LockPackage
+
+	// RevisionDate is the commit date of the locked revision, or the zero time
+	// if the package's remote isn't in the cache.
+	RevisionDate time.Time
+	// TreeHash is the hash of the package's vendored files, in the same format
+	// as the lock file.
+	TreeHash string
+	// Files are the package's vendored files, sorted by path.
+	Files []File
+	// Licenses are the license files kept in the package.
+	Licenses []download.LicenseFile
+}
+
+// File is a vendored file.
+type File struct {
+	// Path is the slash separated path of the file relative to the project.
+	Path   string
+	SHA1   string
+	SHA256 string
+}
+
+// Collect describes each package of the project's lock file using the vendor
+// directory and cache. Remotes are never fetched.
+func Collect(p *download.Project, logger *log.Logger) (*BOM, error) {
+	l, err := p.LoadLock()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BOM{Project: filepath.Base(p.Dir), Created: time.Now()}
+	for _, lockPkg := range l.Import {
+		pkg := Package{LockPackage: lockPkg}
+		if pkg.TreeHash, err = p.HashPackage(lockPkg.Package); err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("package %s is missing from the vendor directory, run 'godl vendor'", lockPkg.Package)
+			}
+			return nil, fmt.Errorf("hash package %s: %v", lockPkg.Package, err)
+		}
+		if pkg.Files, err = vendoredFiles(p, lockPkg.Package); err != nil {
+			return nil, fmt.Errorf("package %s: %v", lockPkg.Package, err)
+		}
+		if pkg.Licenses, err = p.LicenseFiles(lockPkg.Package); err != nil {
+			return nil, fmt.Errorf("package %s: %v", lockPkg.Package, err)
+		}
+		if pkg.RevisionDate, err = p.RevisionDate(lockPkg); err != nil {
+			logger.Printf("warning: no revision date for %s: %v", lockPkg.Package, err)
+		}
+		b.Packages = append(b.Packages, pkg)
+	}
+
+	if b.Imports, err = p.VendorImports(); err != nil {
+		return nil, fmt.Errorf("listing imports of vendored packages: %v", err)
+	}
+	files, err := p.ProjectImports()
+	if err != nil {
+		return nil, fmt.Errorf("listing imports of project: %v", err)
+	}
+	for _, imports := range files {
+		for _, importPath := range imports {
+			if _, ok := b.Imports[importPath]; ok && !download.ContainsString(b.ProjectImports, importPath) {
+				b.ProjectImports = append(b.ProjectImports, importPath)
+			}
+		}
+	}
+	sort.Strings(b.ProjectImports)
+	return b, nil
+}
+
+// vendoredFiles hashes every regular file of a vendored package.
+func vendoredFiles(p *download.Project, importPath string) ([]File, error) {
+	var files []File
+	err := filepath.Walk(p.PackageDir(importPath), func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h1, h256 := sha1.New(), sha256.New()
+		if _, err := io.Copy(io.MultiWriter(h1, h256), f); err != nil {
+			return err
+		}
+		files = append(files, File{
+			Path:   filepath.ToSlash(rel),
+			SHA1:   fmt.Sprintf("%x", h1.Sum(nil)),
+			SHA256: fmt.Sprintf("%x", h256.Sum(nil)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
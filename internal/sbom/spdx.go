@@ -0,0 +1,206 @@
+package sbom
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+// spdxVersion is the version of the SPDX specification documents follow.
+const spdxVersion = "SPDX-2.3"
+
+const noAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Files             []spdxFile         `json:"files,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                    string                `json:"name"`
+	SPDXID                  string                `json:"SPDXID"`
+	VersionInfo             string                `json:"versionInfo,omitempty"`
+	DownloadLocation        string                `json:"downloadLocation"`
+	SourceInfo              string                `json:"sourceInfo,omitempty"`
+	ReleaseDate             string                `json:"releaseDate,omitempty"`
+	FilesAnalyzed           bool                  `json:"filesAnalyzed"`
+	PackageVerificationCode *spdxVerificationCode `json:"packageVerificationCode,omitempty"`
+	Checksums               []spdxChecksum        `json:"checksums,omitempty"`
+	LicenseConcluded        string                `json:"licenseConcluded"`
+	LicenseDeclared         string                `json:"licenseDeclared"`
+	LicenseInfoFromFiles    []string              `json:"licenseInfoFromFiles,omitempty"`
+	CopyrightText           string                `json:"copyrightText"`
+	HasFiles                []string              `json:"hasFiles,omitempty"`
+}
+
+type spdxVerificationCode struct {
+	Value string `json:"packageVerificationCodeValue"`
+}
+
+type spdxChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+type spdxFile struct {
+	FileName           string         `json:"fileName"`
+	SPDXID             string         `json:"SPDXID"`
+	Checksums          []spdxChecksum `json:"checksums"`
+	LicenseInfoInFiles []string       `json:"licenseInfoInFiles,omitempty"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// WriteSPDX writes an SPDX JSON document. The project is the document's root
+// package, which depends on every other package.
+func WriteSPDX(w io.Writer, b *BOM) error {
+	doc := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              b.Project,
+		DocumentNamespace: spdxNamespace(b.Project, b.Packages),
+		CreationInfo: spdxCreationInfo{
+			Created:  b.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: godl"},
+		},
+	}
+
+	root := spdxPackage{
+		Name:             b.Project,
+		SPDXID:           "SPDXRef-Project",
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+	}
+	doc.Packages = append(doc.Packages, root)
+	doc.Relationships = append(doc.Relationships, spdxRelationship{doc.SPDXID, "DESCRIBES", root.SPDXID})
+
+	// Package names that only differ by characters replaced in identifiers,
+	// such as "a/b" and "a-b", are given a numbered suffix.
+	ids := map[string]bool{root.SPDXID: true}
+	for _, pkg := range b.Packages {
+		id := "SPDXRef-Package-" + spdxIDString(pkg.Package)
+		for n := 2; ids[id]; n++ {
+			id = fmt.Sprintf("SPDXRef-Package-%s-%d", spdxIDString(pkg.Package), n)
+		}
+		ids[id] = true
+
+		p := spdxPackage{
+			Name:             pkg.Package,
+			SPDXID:           id,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: spdxDownloadLocation(download.RemoteOf(download.ManifestPackage{Package: pkg.Package, Remote: pkg.Remote}), pkg.Rev()),
+			SourceInfo:       "vendored from revision " + pkg.Rev(),
+			FilesAnalyzed:    true,
+			Checksums:        []spdxChecksum{{"SHA256", download.HashHex(pkg.TreeHash)}},
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+		}
+		if !pkg.RevisionDate.IsZero() {
+			p.ReleaseDate = pkg.RevisionDate.UTC().Format(time.RFC3339)
+		}
+		if ids := download.LicenseIDs(pkg.Licenses); len(ids) > 0 {
+			p.LicenseDeclared = strings.Join(ids, " AND ")
+			p.LicenseInfoFromFiles = ids
+		} else {
+			p.LicenseInfoFromFiles = []string{noAssertion}
+		}
+
+		licenses := make(map[string][]string)
+		for _, l := range pkg.Licenses {
+			licenses[pkg.Package+"/"+l.Path] = l.Licenses
+		}
+		var sha1s []string
+		for _, f := range pkg.Files {
+			file := spdxFile{
+				FileName: "./" + f.Path,
+				SPDXID:   fmt.Sprintf("SPDXRef-File-%d", len(doc.Files)+1),
+				Checksums: []spdxChecksum{
+					{"SHA1", f.SHA1},
+					{"SHA256", f.SHA256},
+				},
+			}
+			file.LicenseInfoInFiles = licenses[strings.TrimPrefix(f.Path, "vendor/")]
+			doc.Files = append(doc.Files, file)
+			p.HasFiles = append(p.HasFiles, file.SPDXID)
+			sha1s = append(sha1s, f.SHA1)
+		}
+		p.PackageVerificationCode = &spdxVerificationCode{verificationCode(sha1s)}
+
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{root.SPDXID, "DEPENDS_ON", p.SPDXID})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(doc)
+}
+
+// verificationCode computes an SPDX package verification code, the SHA-1 of
+// the package's sorted file SHA-1s.
+func verificationCode(sha1s []string) string {
+	sorted := append([]string{}, sha1s...)
+	sort.Strings(sorted)
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(sorted, ""))))
+}
+
+// spdxNamespace returns a unique URI for the document. It's derived from the
+// packages so documents of the same lock file share a namespace.
+func spdxNamespace(project string, pkgs []Package) string {
+	h := sha256.New()
+	fmt.Fprintln(h, project)
+	for _, pkg := range pkgs {
+		fmt.Fprintln(h, pkg.Package, pkg.Rev(), pkg.TreeHash)
+	}
+	return fmt.Sprintf("https://spdx.org/spdxdocs/%s-%x", spdxIDString(project), h.Sum(nil)[:16])
+}
+
+var spdxIDInvalid = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// spdxIDString replaces characters that aren't allowed in SPDX identifiers.
+func spdxIDString(s string) string {
+	return spdxIDInvalid.ReplaceAllString(s, "-")
+}
+
+// scpRemote matches scp-like git remotes such as "git@github.com:foo/bar.git".
+var scpRemote = regexp.MustCompile(`^([^@/:]+@)?([^/:]+):(.+)$`)
+
+// spdxDownloadLocation formats a git remote and revision as an SPDX download
+// location.
+func spdxDownloadLocation(remote, rev string) string {
+	if !strings.Contains(remote, "://") {
+		m := scpRemote.FindStringSubmatch(remote)
+		if m == nil {
+			return noAssertion
+		}
+		remote = "ssh://" + m[1] + m[2] + "/" + m[3]
+	}
+	return "git+" + remote + "@" + rev
+}
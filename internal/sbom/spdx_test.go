@@ -0,0 +1,96 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericchiang/godl/internal/download"
+)
+
+func TestSPDXDownloadLocation(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"https://github.com/foo/bar", "git+https://github.com/foo/bar@abc"},
+		{"git@github.com:foo/bar.git", "git+ssh://git@github.com/foo/bar.git@abc"},
+		{"not a remote", noAssertion},
+	}
+	for _, test := range tests {
+		if got := spdxDownloadLocation(test.remote, "abc"); got != test.want {
+			t.Errorf("spdxDownloadLocation(%q), want=%q, got=%q", test.remote, test.want, got)
+		}
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	b := &BOM{Project: "project", Created: time.Unix(0, 0)}
+	b.Packages = []Package{
+		{
+			LockPackage: download.LockPackage{Package: "github.com/foo/bar", Version: "v1.0.0", Revision: "abc"},
+			TreeHash:    "sha256:0123",
+			Files: []File{
+				{Path: "vendor/github.com/foo/bar/LICENSE", SHA1: "bb", SHA256: "02"},
+				{Path: "vendor/github.com/foo/bar/bar.go", SHA1: "aa", SHA256: "01"},
+			},
+			Licenses: []download.LicenseFile{{Path: "LICENSE", Licenses: []string{"MIT"}}},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteSPDX(buf, b); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) != 2 || len(doc.Files) != 2 || len(doc.Relationships) != 2 {
+		t.Fatalf("expected 2 packages, files and relationships, got %d, %d and %d",
+			len(doc.Packages), len(doc.Files), len(doc.Relationships))
+	}
+	p := doc.Packages[1]
+	if p.LicenseDeclared != "MIT" {
+		t.Errorf("expected declared license MIT, got %q", p.LicenseDeclared)
+	}
+	if want := verificationCode([]string{"aa", "bb"}); p.PackageVerificationCode.Value != want {
+		t.Errorf("expected verification code %s, got %s", want, p.PackageVerificationCode.Value)
+	}
+	if got := doc.Files[0].LicenseInfoInFiles; len(got) != 1 || got[0] != "MIT" {
+		t.Errorf("expected license file to be marked MIT, got %q", got)
+	}
+}
+
+func TestWriteSPDXUniqueIDs(t *testing.T) {
+	b := &BOM{Project: "project", Created: time.Unix(0, 0)}
+	for _, name := range []string{"github.com/foo/bar-baz", "github.com/foo/bar/baz", "github.com/foo/bar_baz"} {
+		b.Packages = append(b.Packages, Package{
+			LockPackage: download.LockPackage{Package: name, Version: "v1.0.0", Revision: "abc"},
+		})
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteSPDX(buf, b); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"SPDXRef-Project",
+		"SPDXRef-Package-github.com-foo-bar-baz",
+		"SPDXRef-Package-github.com-foo-bar-baz-2",
+		"SPDXRef-Package-github.com-foo-bar-baz-3",
+	}
+	var got []string
+	for _, p := range doc.Packages {
+		got = append(got, p.SPDXID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want=%q, got=%q", want, got)
+	}
+}
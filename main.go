@@ -8,7 +8,7 @@ import (
 )
 
 func main() {
-	if err := cmd.New().Execute(); err != nil {
+	if err := cmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}